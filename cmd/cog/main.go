@@ -1,24 +1,81 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"os"
 	"path/filepath"
 
+	"cog/internal/agents"
+	"cog/internal/backends"
+	"cog/internal/config"
 	"cog/internal/storage"
+	"cog/internal/tools"
 	"cog/internal/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/sashabaranov/go-openai"
 )
 
+// buildRegistry registers every backend cog knows how to talk to. Ollama is
+// always available (it degrades to connection errors if no local server is
+// running); OpenAI, Anthropic and Google only register when their API key is
+// set, so an unconfigured provider never shows up in the model picker or as
+// a conversation's default.
+func buildRegistry(cfg config.Config) *backends.Registry {
+	registry := backends.NewRegistry(cfg.Backend)
+
+	if key := os.Getenv("OPENAI_API_KEY"); key != "" {
+		registry.Register(backends.NewOpenAIBackend(openai.NewClient(key)))
+	}
+	if key := os.Getenv("ANTHROPIC_API_KEY"); key != "" {
+		registry.Register(backends.NewAnthropicBackend(key))
+	}
+	if key := os.Getenv("GOOGLE_API_KEY"); key != "" {
+		registry.Register(backends.NewGoogleBackend(key))
+	}
+	registry.Register(backends.NewOllamaBackend(os.Getenv("OLLAMA_HOST")))
+
+	return registry
+}
+
+// buildToolRegistry registers cog's built-in toolbox. The filesystem tools
+// are always available; exec is opt-in via COG_ENABLE_EXEC since letting a
+// model run arbitrary shell commands is a much bigger trust boundary, and an
+// agent still only reaches it if its own Tools allowlist names "exec".
+func buildToolRegistry() *tools.Registry {
+	registry := tools.NewRegistry()
+	registry.Register(tools.ReadFileTool{})
+	registry.Register(tools.WriteFileTool{})
+	registry.Register(tools.ModifyFileTool{})
+	registry.Register(tools.ListDirTool{})
+	if os.Getenv("COG_ENABLE_EXEC") != "" {
+		registry.Register(tools.ExecTool{})
+	}
+	return registry
+}
+
 func main() {
-	if os.Getenv("OPENAI_API_KEY") == "" {
-		log.Fatal("OPENAI_API_KEY environment variable is required")
+	var agentName string
+	flag.StringVar(&agentName, "agent", "", "agent to use for new conversations")
+	flag.StringVar(&agentName, "a", "", "shorthand for -agent")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
 	}
 
-	// Initialize OpenAI client
-	client := openai.NewClient(os.Getenv("OPENAI_API_KEY"))
+	registry := buildRegistry(cfg)
+	if _, err := registry.Get(cfg.Backend); err != nil {
+		log.Fatalf("default backend %q is not configured: %v", cfg.Backend, err)
+	}
+
+	agentList, err := agents.LoadAll()
+	if err != nil {
+		log.Fatal("Failed to load agents:", err)
+	}
+	defaultAgent := agents.Find(agentList, agentName).Name
 
 	// Initialize database
 	homeDir, err := os.UserHomeDir()
@@ -39,7 +96,8 @@ func main() {
 	defer db.Close()
 
 	// Create UI model
-	model := ui.NewModel(client, db)
+	toolRegistry := buildToolRegistry()
+	model := ui.NewModel(registry, db, cfg.Backend, cfg.Model, agentList, defaultAgent, toolRegistry, cfg.MarkdownStyle)
 
 	// Start the application
 	p := tea.NewProgram(model, tea.WithAltScreen())
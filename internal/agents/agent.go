@@ -0,0 +1,104 @@
+// Package agents loads cog's agent definitions — named system prompts with
+// a restricted set of tools — from ~/.cog/agents/*.yaml.
+package agents
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Agent pairs a system prompt with the subset of tools it's allowed to call,
+// e.g. a "coding" agent with file tools vs. a "writing" agent with none.
+type Agent struct {
+	Name         string   `yaml:"name"`
+	SystemPrompt string   `yaml:"system_prompt"`
+	Tools        []string `yaml:"tools"`
+	Model        string   `yaml:"model"`
+}
+
+// Default is the built-in agent used when ~/.cog/agents has none defined
+// yet, matching cog's original hard-coded system prompt.
+func Default() Agent {
+	return Agent{
+		Name:         "default",
+		SystemPrompt: "You are a helpful AI assistant. Provide clear, concise, and helpful responses.",
+	}
+}
+
+// Dir returns the directory agent definitions are loaded from, ~/.cog/agents.
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cog", "agents"), nil
+}
+
+// LoadAll reads every *.yaml file in Dir and returns the agents they define,
+// always including Default() first. A missing Dir isn't an error — it just
+// means no custom agents have been defined yet.
+func LoadAll() ([]Agent, error) {
+	all := []Agent{Default()}
+
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return all, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".yaml" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var agent Agent
+		if err := yaml.Unmarshal(data, &agent); err != nil {
+			return nil, err
+		}
+		if agent.Name == "" {
+			agent.Name = strings.TrimSuffix(entry.Name(), ".yaml")
+		}
+		all = append(all, agent)
+	}
+
+	return all, nil
+}
+
+// Find returns the agent named name from all, or Default() if name is empty
+// or doesn't match any of them.
+func Find(all []Agent, name string) Agent {
+	if name != "" {
+		for _, a := range all {
+			if a.Name == name {
+				return a
+			}
+		}
+	}
+	return Default()
+}
+
+// HasTool reports whether tool is in the agent's allowlist. An agent with no
+// Tools configured allows none, e.g. a writing agent that shouldn't touch
+// the filesystem.
+func (a Agent) HasTool(tool string) bool {
+	for _, t := range a.Tools {
+		if t == tool {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package backends
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// AnthropicBackend talks to the Anthropic Messages API
+// (https://docs.anthropic.com/en/api/messages) directly over HTTP, since
+// there's no Anthropic Go SDK wired into this project.
+type AnthropicBackend struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewAnthropicBackend builds a backend that authenticates with apiKey.
+func NewAnthropicBackend(apiKey string) *AnthropicBackend {
+	return &AnthropicBackend{apiKey: apiKey, httpClient: http.DefaultClient}
+}
+
+func (b *AnthropicBackend) Name() string { return "anthropic" }
+
+func (b *AnthropicBackend) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	// The Messages API has no models-list endpoint; the lineup is small
+	// enough to hardcode until that changes.
+	return []ModelInfo{
+		{ID: "claude-3-5-sonnet-latest", Name: "Claude 3.5 Sonnet"},
+		{ID: "claude-3-5-haiku-latest", Name: "Claude 3.5 Haiku"},
+		{ID: "claude-3-opus-latest", Name: "Claude 3 Opus"},
+	}, nil
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Text string `json:"text"`
+	} `json:"delta"`
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (b *AnthropicBackend) Chat(ctx context.Context, req ChatRequest) (<-chan Chunk, error) {
+	messages := make([]anthropicMessage, 0, len(req.Messages))
+	var system string
+	for _, m := range req.Messages {
+		if m.Role == "system" {
+			system = m.Content
+			continue
+		}
+		messages = append(messages, anthropicMessage{Role: m.Role, Content: m.Content})
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"model":      req.Model,
+		"system":     system,
+		"messages":   messages,
+		"max_tokens": 1024,
+		"stream":     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.anthropic.com/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", b.apiKey)
+	httpReq.Header.Set("anthropic-version", "2023-06-01")
+
+	resp, err := b.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("anthropic: unexpected status %s", resp.Status)
+	}
+
+	out := make(chan Chunk)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+		err := scanSSELines(resp.Body, func(payload string) error {
+			var event anthropicStreamEvent
+			if err := json.Unmarshal([]byte(payload), &event); err != nil {
+				return err
+			}
+			if event.Type == "error" {
+				return fmt.Errorf("anthropic: %s", event.Error.Message)
+			}
+			if event.Type == "content_block_delta" && event.Delta.Text != "" {
+				out <- Chunk{Content: event.Delta.Text}
+			}
+			return nil
+		})
+		if err != nil {
+			out <- Chunk{Err: err}
+		}
+	}()
+
+	return out, nil
+}
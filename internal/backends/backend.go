@@ -0,0 +1,118 @@
+// Package backends abstracts away the differences between the various chat
+// completion providers cog can talk to, so the UI only ever deals with the
+// Backend interface and never a specific provider's SDK or wire format.
+package backends
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ChatMessage is a role/content pair independent of any specific provider's
+// wire format. ToolCallID and Name are set on a "tool" role message (the
+// result of running a tool) and on an "assistant" message that is itself a
+// tool call, matching OpenAI's function-calling conversation shape.
+type ChatMessage struct {
+	Role       string
+	Content    string
+	ToolCallID string
+	Name       string
+	ToolCalls  []ToolCall
+}
+
+// ToolDef describes a function the model may call mid-conversation,
+// independent of any specific provider's wire format.
+type ToolDef struct {
+	Name        string
+	Description string
+	Parameters  json.RawMessage
+}
+
+// ToolCall is one function call the model asked for while streaming a
+// reply.
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments string
+}
+
+// ChatRequest is everything a Backend needs to start a chat completion.
+type ChatRequest struct {
+	Model    string
+	Messages []ChatMessage
+	Tools    []ToolDef
+}
+
+// Chunk is one incremental piece of a streamed reply. A non-nil Err ends
+// the stream. ToolCalls is set instead of Content when the model wants to
+// call one or more tools rather than reply directly; it always arrives as
+// the stream's last chunk.
+type Chunk struct {
+	Content   string
+	ToolCalls []ToolCall
+	Err       error
+}
+
+// ModelInfo describes a model a Backend can serve.
+type ModelInfo struct {
+	ID   string
+	Name string
+}
+
+// Backend is anything that can hold a chat conversation and stream back a
+// reply, whether it's a hosted API (OpenAI, Anthropic, Google) or a local
+// server (Ollama). cog drives whichever backend a conversation is
+// configured to use without needing to know which one it is.
+type Backend interface {
+	Name() string
+	ListModels(ctx context.Context) ([]ModelInfo, error)
+	Chat(ctx context.Context, req ChatRequest) (<-chan Chunk, error)
+}
+
+// Registry resolves a Backend by name, e.g. the one recorded on a
+// conversation or picked from the model picker.
+type Registry struct {
+	backends map[string]Backend
+	def      string
+}
+
+// NewRegistry creates an empty registry that falls back to def when Get is
+// called with an empty name.
+func NewRegistry(def string) *Registry {
+	return &Registry{backends: make(map[string]Backend), def: def}
+}
+
+// Register adds b to the registry under b.Name().
+func (r *Registry) Register(b Backend) {
+	r.backends[b.Name()] = b
+}
+
+// Get looks up a backend by name, falling back to the registry's default
+// when name is empty.
+func (r *Registry) Get(name string) (Backend, error) {
+	if name == "" {
+		name = r.def
+	}
+	b, ok := r.backends[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown backend %q", name)
+	}
+	return b, nil
+}
+
+// Default returns the name of the registry's fallback backend.
+func (r *Registry) Default() string {
+	return r.def
+}
+
+// Names returns the names of every registered backend, sorted.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.backends))
+	for name := range r.backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
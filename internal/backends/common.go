@@ -0,0 +1,31 @@
+package backends
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// scanSSELines reads Server-Sent Events "data: ..." lines from r and invokes
+// handle with the payload of each one, stopping at the "data: [DONE]"
+// sentinel some providers send to mark the end of a stream. It's shared by
+// the providers (Anthropic, Google) that stream over SSE rather than
+// newline-delimited JSON.
+func scanSSELines(r io.Reader, handle func(payload string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		payload := strings.TrimPrefix(line, "data: ")
+		if payload == "[DONE]" {
+			return nil
+		}
+		if err := handle(payload); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,111 @@
+package backends
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// GoogleBackend talks to the Gemini API's streamGenerateContent endpoint.
+type GoogleBackend struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewGoogleBackend builds a backend that authenticates with apiKey.
+func NewGoogleBackend(apiKey string) *GoogleBackend {
+	return &GoogleBackend{apiKey: apiKey, httpClient: http.DefaultClient}
+}
+
+func (b *GoogleBackend) Name() string { return "google" }
+
+func (b *GoogleBackend) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	return []ModelInfo{
+		{ID: "gemini-1.5-pro", Name: "Gemini 1.5 Pro"},
+		{ID: "gemini-1.5-flash", Name: "Gemini 1.5 Flash"},
+	}, nil
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiStreamChunk struct {
+	Candidates []struct {
+		Content geminiContent `json:"content"`
+	} `json:"candidates"`
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (b *GoogleBackend) Chat(ctx context.Context, req ChatRequest) (<-chan Chunk, error) {
+	contents := make([]geminiContent, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		role := m.Role
+		switch role {
+		case "assistant":
+			role = "model"
+		case "system":
+			// Gemini has no system role; fold it into a user turn instead.
+			role = "user"
+		}
+		contents = append(contents, geminiContent{Role: role, Parts: []geminiPart{{Text: m.Content}}})
+	}
+
+	body, err := json.Marshal(map[string]any{"contents": contents})
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:streamGenerateContent?alt=sse&key=%s", req.Model, b.apiKey)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("google: unexpected status %s", resp.Status)
+	}
+
+	out := make(chan Chunk)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+		err := scanSSELines(resp.Body, func(payload string) error {
+			var chunk geminiStreamChunk
+			if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+				return err
+			}
+			if chunk.Error.Message != "" {
+				return fmt.Errorf("google: %s", chunk.Error.Message)
+			}
+			for _, c := range chunk.Candidates {
+				for _, p := range c.Content.Parts {
+					if p.Text != "" {
+						out <- Chunk{Content: p.Text}
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			out <- Chunk{Err: err}
+		}
+	}()
+
+	return out, nil
+}
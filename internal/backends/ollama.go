@@ -0,0 +1,128 @@
+package backends
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// OllamaBackend talks to a local Ollama server's /api/chat endpoint, so the
+// same TUI can drive models running entirely on the user's machine.
+type OllamaBackend struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewOllamaBackend builds a backend pointed at baseURL. An empty baseURL
+// falls back to Ollama's default local address.
+func NewOllamaBackend(baseURL string) *OllamaBackend {
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
+	}
+	return &OllamaBackend{baseURL: baseURL, httpClient: http.DefaultClient}
+}
+
+func (b *OllamaBackend) Name() string { return "ollama" }
+
+type ollamaModel struct {
+	Name string `json:"name"`
+}
+
+func (b *OllamaBackend) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, b.baseURL+"/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var parsed struct {
+		Models []ollamaModel `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, err
+	}
+
+	models := make([]ModelInfo, 0, len(parsed.Models))
+	for _, m := range parsed.Models {
+		models = append(models, ModelInfo{ID: m.Name, Name: m.Name})
+	}
+	return models, nil
+}
+
+type ollamaChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ollamaChatChunk struct {
+	Message ollamaChatMessage `json:"message"`
+	Done    bool              `json:"done"`
+	Error   string            `json:"error"`
+}
+
+func (b *OllamaBackend) Chat(ctx context.Context, req ChatRequest) (<-chan Chunk, error) {
+	messages := make([]ollamaChatMessage, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		messages = append(messages, ollamaChatMessage{Role: m.Role, Content: m.Content})
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"model":    req.Model,
+		"messages": messages,
+		"stream":   true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, b.baseURL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("ollama: unexpected status %s", resp.Status)
+	}
+
+	out := make(chan Chunk)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+		decoder := json.NewDecoder(resp.Body)
+		for {
+			var chunk ollamaChatChunk
+			if err := decoder.Decode(&chunk); err != nil {
+				if !errors.Is(err, io.EOF) {
+					out <- Chunk{Err: err}
+				}
+				return
+			}
+			if chunk.Error != "" {
+				out <- Chunk{Err: fmt.Errorf("ollama: %s", chunk.Error)}
+				return
+			}
+			if chunk.Message.Content != "" {
+				out <- Chunk{Content: chunk.Message.Content}
+			}
+			if chunk.Done {
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,136 @@
+package backends
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// OpenAIBackend adapts the existing go-openai client to the Backend
+// interface.
+type OpenAIBackend struct {
+	client *openai.Client
+}
+
+// NewOpenAIBackend wraps an already-constructed go-openai client.
+func NewOpenAIBackend(client *openai.Client) *OpenAIBackend {
+	return &OpenAIBackend{client: client}
+}
+
+func (b *OpenAIBackend) Name() string { return "openai" }
+
+func (b *OpenAIBackend) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	list, err := b.client.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	models := make([]ModelInfo, 0, len(list.Models))
+	for _, m := range list.Models {
+		models = append(models, ModelInfo{ID: m.ID, Name: m.ID})
+	}
+	return models, nil
+}
+
+func (b *OpenAIBackend) Chat(ctx context.Context, req ChatRequest) (<-chan Chunk, error) {
+	messages := make([]openai.ChatCompletionMessage, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		msg := openai.ChatCompletionMessage{
+			Role:       m.Role,
+			Content:    m.Content,
+			ToolCallID: m.ToolCallID,
+			Name:       m.Name,
+		}
+		for _, tc := range m.ToolCalls {
+			msg.ToolCalls = append(msg.ToolCalls, openai.ToolCall{
+				ID:       tc.ID,
+				Type:     openai.ToolTypeFunction,
+				Function: openai.FunctionCall{Name: tc.Name, Arguments: tc.Arguments},
+			})
+		}
+		messages = append(messages, msg)
+	}
+
+	var tools []openai.Tool
+	for _, t := range req.Tools {
+		tools = append(tools, openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.Parameters,
+			},
+		})
+	}
+
+	stream, err := b.client.CreateChatCompletionStream(ctx, openai.ChatCompletionRequest{
+		Model:     req.Model,
+		Messages:  messages,
+		MaxTokens: 1000,
+		Tools:     tools,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan Chunk)
+	go func() {
+		defer close(out)
+		defer stream.Close()
+
+		// toolCalls accumulates each call's id/name/arguments by index, since
+		// OpenAI streams them piecemeal across several deltas; order tracks
+		// the index each call first appeared in so they're emitted in turn.
+		toolCalls := map[int]*ToolCall{}
+		var order []int
+
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					out <- Chunk{Err: err}
+					return
+				}
+				break
+			}
+			if len(resp.Choices) == 0 {
+				continue
+			}
+
+			delta := resp.Choices[0].Delta
+			if delta.Content != "" {
+				out <- Chunk{Content: delta.Content}
+			}
+			for _, tc := range delta.ToolCalls {
+				idx := 0
+				if tc.Index != nil {
+					idx = *tc.Index
+				}
+				call, ok := toolCalls[idx]
+				if !ok {
+					call = &ToolCall{}
+					toolCalls[idx] = call
+					order = append(order, idx)
+				}
+				if tc.ID != "" {
+					call.ID = tc.ID
+				}
+				if tc.Function.Name != "" {
+					call.Name = tc.Function.Name
+				}
+				call.Arguments += tc.Function.Arguments
+			}
+		}
+
+		if len(order) > 0 {
+			calls := make([]ToolCall, 0, len(order))
+			for _, idx := range order {
+				calls = append(calls, *toolCalls[idx])
+			}
+			out <- Chunk{ToolCalls: calls}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,57 @@
+// Package config loads cog's on-disk settings file.
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Config is cog's settings file, read from ~/.cog/config.yaml.
+type Config struct {
+	Backend string `yaml:"backend"`
+	Model   string `yaml:"model"`
+
+	// MarkdownStyle selects the glamour style used to render assistant
+	// messages: "dark", "light", "notty", or a path to a custom glamour
+	// style JSON file.
+	MarkdownStyle string `yaml:"markdown_style"`
+}
+
+// Default returns the settings used when no config file exists yet.
+func Default() Config {
+	return Config{Backend: "openai", Model: "gpt-3.5-turbo", MarkdownStyle: "dark"}
+}
+
+// Path returns the default config file location, ~/.cog/config.yaml.
+func Path() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cog", "config.yaml"), nil
+}
+
+// Load reads the config file at Path, falling back to Default() if it
+// doesn't exist yet.
+func Load() (Config, error) {
+	path, err := Path()
+	if err != nil {
+		return Config{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Default(), nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg := Default()
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
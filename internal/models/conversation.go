@@ -7,11 +7,27 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 )
 
-// Message represents a single chat message
+// Message represents a single chat message.
+//
+// ParentID and SelectedChildID turn a conversation's messages into a tree:
+// editing or retrying an earlier message creates a sibling under the same
+// parent rather than overwriting history, and SelectedChildID records which
+// sibling is currently part of the active path. ID is zero until the
+// message has been persisted.
+//
+// ToolCallID and Name are set on a tool-calling turn: an assistant message
+// requesting a call has Name set to the tool and Content set to its
+// arguments, and the Role "tool" message that follows carries the same
+// ToolCallID/Name with Content set to the tool's result.
 type Message struct {
-	Role    string
-	Content string
-	Time    time.Time
+	ID              int64
+	Role            string
+	Content         string
+	Time            time.Time
+	ParentID        *int64
+	SelectedChildID *int64
+	ToolCallID      string
+	Name            string
 }
 
 // Conversation represents a chat conversation with messages
@@ -20,6 +36,21 @@ type Conversation struct {
 	Name     string
 	Messages []Message
 	Created  time.Time
+
+	// Backend and Model pin this conversation to a specific provider and
+	// model, e.g. Backend "anthropic", Model "claude-3-5-sonnet-latest", so
+	// different conversations can talk to different providers.
+	Backend string
+	Model   string
+
+	// Agent names the agents.Agent this conversation uses for its system
+	// prompt and tool allowlist. Empty means the built-in default agent.
+	Agent string
+
+	// SystemPrompt overrides the agent's system prompt for this conversation
+	// alone, e.g. one picked from the prompt library. Empty means fall back
+	// to the agent's SystemPrompt.
+	SystemPrompt string
 }
 
 // FilterValue implements list.Item interface for the conversation list
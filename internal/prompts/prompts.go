@@ -0,0 +1,92 @@
+// Package prompts loads and saves cog's prompt library — named system
+// prompts a user can apply to any conversation — from ~/.cog/prompts.yaml.
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Prompt pairs a name with the system prompt text it expands to.
+type Prompt struct {
+	Name string `yaml:"name"`
+	Text string `yaml:"text"`
+}
+
+// Path returns the prompt library's file location, ~/.cog/prompts.yaml.
+func Path() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cog", "prompts.yaml"), nil
+}
+
+// LoadAll reads every prompt defined in Path. A missing file isn't an
+// error — it just means the library is empty so far.
+func LoadAll() ([]Prompt, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var wrapper struct {
+		Prompts []Prompt `yaml:"prompts"`
+	}
+	if err := yaml.Unmarshal(data, &wrapper); err != nil {
+		return nil, err
+	}
+	return wrapper.Prompts, nil
+}
+
+// Save writes text under name into the library, replacing any existing
+// prompt of the same name, and persists the result to Path.
+func Save(name, text string) error {
+	all, err := LoadAll()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i := range all {
+		if all[i].Name == name {
+			all[i].Text = text
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		all = append(all, Prompt{Name: name, Text: text})
+	}
+
+	return saveAll(all)
+}
+
+func saveAll(all []Prompt) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(struct {
+		Prompts []Prompt `yaml:"prompts"`
+	}{Prompts: all})
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
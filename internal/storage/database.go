@@ -2,6 +2,7 @@ package storage
 
 import (
 	"database/sql"
+	"strings"
 	"time"
 
 	"cog/internal/models"
@@ -52,16 +53,77 @@ func (d *Database) createTables() error {
 	CREATE INDEX IF NOT EXISTS idx_messages_conversation_id ON messages(conversation_id);
 	CREATE INDEX IF NOT EXISTS idx_conversations_updated_at ON conversations(updated_at DESC);`
 
-	for _, query := range []string{conversationsTable, messagesTable, indexTable} {
+	// messages_fts is an external-content FTS5 index over messages: its rowid
+	// matches messages.id directly (content_rowid='id'), so the triggers below
+	// just forward each insert/update/delete onto it instead of maintaining a
+	// separate copy of the text. modernc.org/sqlite compiles FTS5 in by
+	// default, so this needs no cgo.
+	ftsTable := `
+	CREATE VIRTUAL TABLE IF NOT EXISTS messages_fts USING fts5(
+		content,
+		conversation_id UNINDEXED,
+		content='messages',
+		content_rowid='id'
+	);`
+
+	ftsTriggers := `
+	CREATE TRIGGER IF NOT EXISTS messages_fts_insert AFTER INSERT ON messages BEGIN
+		INSERT INTO messages_fts(rowid, content, conversation_id) VALUES (new.id, new.content, new.conversation_id);
+	END;
+	CREATE TRIGGER IF NOT EXISTS messages_fts_update AFTER UPDATE ON messages BEGIN
+		INSERT INTO messages_fts(messages_fts, rowid, content, conversation_id) VALUES ('delete', old.id, old.content, old.conversation_id);
+		INSERT INTO messages_fts(rowid, content, conversation_id) VALUES (new.id, new.content, new.conversation_id);
+	END;
+	CREATE TRIGGER IF NOT EXISTS messages_fts_delete AFTER DELETE ON messages BEGIN
+		INSERT INTO messages_fts(messages_fts, rowid, content, conversation_id) VALUES ('delete', old.id, old.content, old.conversation_id);
+	END;`
+
+	// Backfill covers messages inserted before messages_fts existed; OR IGNORE
+	// makes it a no-op once every row already has a matching FTS entry, so
+	// re-running it on every startup is cheap.
+	ftsBackfill := `INSERT OR IGNORE INTO messages_fts(rowid, content, conversation_id) SELECT id, content, conversation_id FROM messages;`
+
+	for _, query := range []string{conversationsTable, messagesTable, indexTable, ftsTable, ftsTriggers, ftsBackfill} {
 		if _, err := d.db.Exec(query); err != nil {
 			return err
 		}
 	}
 
+	return d.migrate()
+}
+
+// migrate applies schema changes introduced after the initial release.
+// ALTER TABLE ADD COLUMN isn't idempotent on its own, so a re-run against an
+// already-migrated database is expected to hit "duplicate column name",
+// which we treat as success.
+func (d *Database) migrate() error {
+	statements := []string{
+		`ALTER TABLE messages ADD COLUMN parent_id INTEGER REFERENCES messages(id)`,
+		`ALTER TABLE messages ADD COLUMN selected_child_id INTEGER REFERENCES messages(id)`,
+		`ALTER TABLE conversations ADD COLUMN backend TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE conversations ADD COLUMN model TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE conversations ADD COLUMN agent TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE messages ADD COLUMN tool_call_id TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE messages ADD COLUMN name TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE conversations ADD COLUMN system_prompt TEXT NOT NULL DEFAULT ''`,
+	}
+	for _, stmt := range statements {
+		if _, err := d.db.Exec(stmt); err != nil && !isDuplicateColumn(err) {
+			return err
+		}
+	}
 	return nil
 }
 
-// SaveConversation saves or updates a conversation and all its messages
+func isDuplicateColumn(err error) bool {
+	return strings.Contains(err.Error(), "duplicate column name")
+}
+
+// SaveConversation upserts a conversation's metadata and appends any new
+// (unpersisted) messages onto its tree, updating already-persisted ones in
+// place. It never deletes a message row: once branching is in play, a
+// message that has fallen out of conv.Messages (the active path) may still
+// be the root of a sibling branch sitting in the database.
 func (d *Database) SaveConversation(conv models.Conversation) error {
 	tx, err := d.db.Begin()
 	if err != nil {
@@ -69,39 +131,62 @@ func (d *Database) SaveConversation(conv models.Conversation) error {
 	}
 	defer tx.Rollback()
 
-	// Insert or update conversation
+	// Upsert instead of INSERT OR REPLACE: the latter is a delete+insert
+	// under the hood, which would cascade-delete every message on a
+	// conversation we've merely renamed.
 	_, err = tx.Exec(`
-		INSERT OR REPLACE INTO conversations (id, name, created_at, updated_at)
-		VALUES (?, ?, ?, ?)`,
-		conv.ID, conv.Name, conv.Created, time.Now())
+		INSERT INTO conversations (id, name, created_at, updated_at, backend, model, agent, system_prompt)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name,
+			updated_at = excluded.updated_at,
+			backend = excluded.backend,
+			model = excluded.model,
+			agent = excluded.agent,
+			system_prompt = excluded.system_prompt`,
+		conv.ID, conv.Name, conv.Created, time.Now(), conv.Backend, conv.Model, conv.Agent, conv.SystemPrompt)
 	if err != nil {
 		return err
 	}
 
-	// Delete existing messages for this conversation
-	_, err = tx.Exec("DELETE FROM messages WHERE conversation_id = ?", conv.ID)
-	if err != nil {
-		return err
-	}
-
-	// Insert all messages
-	for _, msg := range conv.Messages {
-		_, err = tx.Exec(`
-			INSERT INTO messages (conversation_id, role, content, created_at)
-			VALUES (?, ?, ?, ?)`,
-			conv.ID, msg.Role, msg.Content, msg.Time)
-		if err != nil {
-			return err
+	var parentID *int64
+	for i := range conv.Messages {
+		msg := &conv.Messages[i]
+		if msg.ID == 0 {
+			res, err := tx.Exec(`
+				INSERT INTO messages (conversation_id, role, content, created_at, parent_id, tool_call_id, name)
+				VALUES (?, ?, ?, ?, ?, ?, ?)`,
+				conv.ID, msg.Role, msg.Content, msg.Time, parentID, msg.ToolCallID, msg.Name)
+			if err != nil {
+				return err
+			}
+			id, err := res.LastInsertId()
+			if err != nil {
+				return err
+			}
+			msg.ID = id
+			msg.ParentID = parentID
+			if parentID != nil {
+				if _, err := tx.Exec(`UPDATE messages SET selected_child_id = ? WHERE id = ?`, id, *parentID); err != nil {
+					return err
+				}
+			}
+		} else {
+			if _, err := tx.Exec(`UPDATE messages SET role = ?, content = ? WHERE id = ?`, msg.Role, msg.Content, msg.ID); err != nil {
+				return err
+			}
 		}
+		parentID = &msg.ID
 	}
 
 	return tx.Commit()
 }
 
-// LoadConversations loads all conversations from the database
+// LoadConversations loads all conversations from the database, each with its
+// currently active message path.
 func (d *Database) LoadConversations() ([]models.Conversation, error) {
 	rows, err := d.db.Query(`
-		SELECT id, name, created_at, updated_at
+		SELECT id, name, created_at, updated_at, backend, model, agent, system_prompt
 		FROM conversations
 		ORDER BY created_at ASC`)
 	if err != nil {
@@ -113,13 +198,12 @@ func (d *Database) LoadConversations() ([]models.Conversation, error) {
 	for rows.Next() {
 		var conv models.Conversation
 		var updatedAt time.Time
-		err := rows.Scan(&conv.ID, &conv.Name, &conv.Created, &updatedAt)
+		err := rows.Scan(&conv.ID, &conv.Name, &conv.Created, &updatedAt, &conv.Backend, &conv.Model, &conv.Agent, &conv.SystemPrompt)
 		if err != nil {
 			return nil, err
 		}
 
-		// Load messages for this conversation
-		messages, err := d.loadMessages(conv.ID)
+		messages, err := d.LoadMessagePath(conv.ID)
 		if err != nil {
 			return nil, err
 		}
@@ -131,38 +215,247 @@ func (d *Database) LoadConversations() ([]models.Conversation, error) {
 	return conversations, nil
 }
 
-func (d *Database) loadMessages(conversationID string) ([]models.Message, error) {
-	rows, err := d.db.Query(`
-		SELECT role, content, created_at
+// LoadMessagePath returns a conversation's currently active message path:
+// starting at the root message (the one with no parent; the most recently
+// created one, if CreateBranch has added a sibling root) and following each
+// message's selected_child_id down to the current leaf.
+func (d *Database) LoadMessagePath(conversationID string) ([]models.Message, error) {
+	root, err := d.loadRootMessage(conversationID)
+	if err != nil || root == nil {
+		return nil, err
+	}
+
+	path := []models.Message{*root}
+	current := root
+	for current.SelectedChildID != nil {
+		child, err := d.loadMessageByID(*current.SelectedChildID)
+		if err != nil {
+			return nil, err
+		}
+		if child == nil {
+			break
+		}
+		path = append(path, *child)
+		current = child
+	}
+
+	return path, nil
+}
+
+func (d *Database) loadRootMessage(conversationID string) (*models.Message, error) {
+	row := d.db.QueryRow(`
+		SELECT id, role, content, created_at, parent_id, selected_child_id, tool_call_id, name
 		FROM messages
-		WHERE conversation_id = ?
-		ORDER BY created_at ASC`,
+		WHERE conversation_id = ? AND parent_id IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1`,
 		conversationID)
+	return scanOptionalMessage(row)
+}
+
+func (d *Database) loadMessageByID(id int64) (*models.Message, error) {
+	row := d.db.QueryRow(`
+		SELECT id, role, content, created_at, parent_id, selected_child_id, tool_call_id, name
+		FROM messages
+		WHERE id = ?`,
+		id)
+	return scanOptionalMessage(row)
+}
+
+func scanOptionalMessage(row *sql.Row) (*models.Message, error) {
+	var msg models.Message
+	var parentID, selectedChildID sql.NullInt64
+	err := row.Scan(&msg.ID, &msg.Role, &msg.Content, &msg.Time, &parentID, &selectedChildID, &msg.ToolCallID, &msg.Name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		msg.ParentID = &parentID.Int64
+	}
+	if selectedChildID.Valid {
+		msg.SelectedChildID = &selectedChildID.Int64
+	}
+	return &msg, nil
+}
+
+// CreateBranch inserts msg as a new sibling of fromMessageID (under the same
+// parent) and marks it as the selected child, so it becomes part of the
+// active path. This is how editing an earlier message or retrying a reply
+// works: the original message and everything under it stays in the
+// database, untouched, as a side branch.
+func (d *Database) CreateBranch(convID string, fromMessageID int64, msg models.Message) (models.Message, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return models.Message{}, err
+	}
+	defer tx.Rollback()
+
+	var parentID sql.NullInt64
+	if err := tx.QueryRow(`SELECT parent_id FROM messages WHERE id = ?`, fromMessageID).Scan(&parentID); err != nil {
+		return models.Message{}, err
+	}
+
+	var parent *int64
+	if parentID.Valid {
+		parent = &parentID.Int64
+	}
+
+	res, err := tx.Exec(`
+		INSERT INTO messages (conversation_id, role, content, created_at, parent_id, tool_call_id, name)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		convID, msg.Role, msg.Content, msg.Time, parent, msg.ToolCallID, msg.Name)
+	if err != nil {
+		return models.Message{}, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return models.Message{}, err
+	}
+
+	if parent != nil {
+		if _, err := tx.Exec(`UPDATE messages SET selected_child_id = ? WHERE id = ?`, id, *parent); err != nil {
+			return models.Message{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Message{}, err
+	}
+
+	msg.ID = id
+	msg.ParentID = parent
+	return msg, nil
+}
+
+// SwitchBranch marks messageID as the selected child of its parent, making
+// it (and whichever of its own descendants are already selected) the active
+// path. A root message has no parent to update, so switching to it is a
+// no-op.
+func (d *Database) SwitchBranch(messageID int64) error {
+	var parentID sql.NullInt64
+	if err := d.db.QueryRow(`SELECT parent_id FROM messages WHERE id = ?`, messageID).Scan(&parentID); err != nil {
+		return err
+	}
+	if !parentID.Valid {
+		return nil
+	}
+	_, err := d.db.Exec(`UPDATE messages SET selected_child_id = ? WHERE id = ?`, messageID, parentID.Int64)
+	return err
+}
+
+// ClearSelectedChild detaches messageID's selected_child_id, so
+// LoadMessagePath's walk down the active path stops at messageID instead of
+// continuing into whichever child it used to point at.
+func (d *Database) ClearSelectedChild(messageID int64) error {
+	_, err := d.db.Exec(`UPDATE messages SET selected_child_id = NULL WHERE id = ?`, messageID)
+	return err
+}
+
+// Siblings returns the IDs of every child sharing messageID's parent
+// (messageID included), ordered by creation time, so the UI can cycle
+// through them with `[` / `]` and render a "[2/3]" position indicator.
+func (d *Database) Siblings(messageID int64) ([]int64, error) {
+	var parentID sql.NullInt64
+	if err := d.db.QueryRow(`SELECT parent_id FROM messages WHERE id = ?`, messageID).Scan(&parentID); err != nil {
+		return nil, err
+	}
+
+	var rows *sql.Rows
+	var err error
+	if parentID.Valid {
+		rows, err = d.db.Query(`SELECT id FROM messages WHERE parent_id = ? ORDER BY created_at ASC`, parentID.Int64)
+	} else {
+		rows, err = d.db.Query(`
+			SELECT id FROM messages
+			WHERE parent_id IS NULL AND conversation_id = (SELECT conversation_id FROM messages WHERE id = ?)
+			ORDER BY created_at ASC`, messageID)
+	}
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var messages []models.Message
+	var ids []int64
 	for rows.Next() {
-		var msg models.Message
-		err := rows.Scan(&msg.Role, &msg.Content, &msg.Time)
-		if err != nil {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
 			return nil, err
 		}
-		messages = append(messages, msg)
+		ids = append(ids, id)
 	}
+	return ids, nil
+}
 
-	return messages, nil
+// SearchHit is one message matching a SearchMessages query: its conversation,
+// a snippet of surrounding text with the match wrapped in <mark></mark>, and
+// its BM25 rank (lower is more relevant).
+type SearchHit struct {
+	MessageID      int64
+	ConversationID string
+	Snippet        string
+	Rank           float64
 }
 
-// DeleteConversation removes a conversation and all its messages
-func (d *Database) DeleteConversation(conversationID string) error {
-	_, err := d.db.Exec("DELETE FROM conversations WHERE id = ?", conversationID)
+// SearchMessages runs query against the messages_fts full-text index,
+// returning hits ordered by BM25 relevance (best match first).
+func (d *Database) SearchMessages(query string) ([]SearchHit, error) {
+	rows, err := d.db.Query(`
+		SELECT rowid, conversation_id, snippet(messages_fts, 0, '<mark>', '</mark>', '...', 8), bm25(messages_fts)
+		FROM messages_fts
+		WHERE messages_fts MATCH ?
+		ORDER BY bm25(messages_fts)
+		LIMIT 50`, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []SearchHit
+	for rows.Next() {
+		var hit SearchHit
+		if err := rows.Scan(&hit.MessageID, &hit.ConversationID, &hit.Snippet, &hit.Rank); err != nil {
+			return nil, err
+		}
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+// RenameConversation updates a conversation's display name, whether set by
+// the user or by GenerateTitle's LLM side-call.
+func (d *Database) RenameConversation(conversationID, name string) error {
+	_, err := d.db.Exec("UPDATE conversations SET name = ? WHERE id = ?", name, conversationID)
 	return err
 }
 
+// DeleteConversation removes a conversation and all its messages. This
+// deletes from messages explicitly, in the same transaction, rather than
+// relying on the schema's ON DELETE CASCADE: SQLite only enforces foreign
+// keys (and so only fires cascades) when a connection has run PRAGMA
+// foreign_keys = ON, which this package never does, so without this the
+// messages would be silently orphaned — still full-text-searchable and
+// still loadable by ID — after their conversation "disappeared".
+func (d *Database) DeleteConversation(conversationID string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM messages WHERE conversation_id = ?", conversationID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM conversations WHERE id = ?", conversationID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 // Close closes the database connection
 func (d *Database) Close() error {
 	return d.db.Close()
-}
\ No newline at end of file
+}
@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+)
+
+// ExecTool runs a shell command in the current working directory and
+// returns its combined output. It's opt-in: cmd/cog/main.go only registers
+// it when the user has explicitly asked for it, since letting a model run
+// arbitrary shell commands is a much bigger trust boundary than the
+// filesystem tools.
+type ExecTool struct{}
+
+func (ExecTool) Name() string { return "exec" }
+
+func (ExecTool) Schema() string {
+	return `{"description":"Run a shell command in the current working directory and return its combined stdout/stderr.","parameters":{"type":"object","properties":{"command":{"type":"string"}},"required":["command"]}}`
+}
+
+func (ExecTool) Run(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Command string `json:"command"`
+	}
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", err
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", params.Command)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("exec: %w", err)
+	}
+	return string(out), nil
+}
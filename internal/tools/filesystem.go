@@ -0,0 +1,172 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// resolvePath confines path to the current working directory: an absolute
+// path is taken as relative to it, and ".." segments that would escape it
+// are rejected, so a tool call can't read or write outside the project cog
+// was launched from.
+func resolvePath(path string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	full := filepath.Join(cwd, path)
+	rel, err := filepath.Rel(cwd, full)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q escapes the working directory", path)
+	}
+	return full, nil
+}
+
+// ReadFileTool reads a file's contents.
+type ReadFileTool struct{}
+
+func (ReadFileTool) Name() string { return "read_file" }
+
+func (ReadFileTool) Schema() string {
+	return `{"description":"Read a file's contents.","parameters":{"type":"object","properties":{"path":{"type":"string","description":"Path relative to the current working directory"}},"required":["path"]}}`
+}
+
+func (ReadFileTool) Run(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Path string `json:"path"`
+	}
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", err
+	}
+	full, err := resolvePath(params.Path)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(full)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// WriteFileTool overwrites a file with new contents, creating it (and any
+// missing parent directories) if it doesn't exist yet.
+type WriteFileTool struct{}
+
+func (WriteFileTool) Name() string { return "write_file" }
+
+func (WriteFileTool) Schema() string {
+	return `{"description":"Write (overwriting) a file's contents.","parameters":{"type":"object","properties":{"path":{"type":"string","description":"Path relative to the current working directory"},"content":{"type":"string"}},"required":["path","content"]}}`
+}
+
+func (WriteFileTool) Run(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Path    string `json:"path"`
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", err
+	}
+	full, err := resolvePath(params.Path)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(full, []byte(params.Content), 0o644); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("wrote %d bytes to %s", len(params.Content), params.Path), nil
+}
+
+// ModifyFileTool replaces a contiguous, 1-indexed, inclusive line range in
+// an existing file with new content — a patch-style edit that doesn't
+// require rewriting the whole file.
+type ModifyFileTool struct{}
+
+func (ModifyFileTool) Name() string { return "modify_file" }
+
+func (ModifyFileTool) Schema() string {
+	return `{"description":"Replace a contiguous range of lines in a file (1-indexed, inclusive).","parameters":{"type":"object","properties":{"path":{"type":"string"},"start_line":{"type":"integer"},"end_line":{"type":"integer"},"content":{"type":"string","description":"Replacement text for the line range"}},"required":["path","start_line","end_line","content"]}}`
+}
+
+func (ModifyFileTool) Run(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Path      string `json:"path"`
+		StartLine int    `json:"start_line"`
+		EndLine   int    `json:"end_line"`
+		Content   string `json:"content"`
+	}
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", err
+	}
+	full, err := resolvePath(params.Path)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(full)
+	if err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(string(data), "\n")
+	if params.StartLine < 1 || params.EndLine < params.StartLine || params.EndLine > len(lines) {
+		return "", fmt.Errorf("line range %d-%d out of bounds for a %d-line file", params.StartLine, params.EndLine, len(lines))
+	}
+
+	newLines := append([]string{}, lines[:params.StartLine-1]...)
+	newLines = append(newLines, strings.Split(params.Content, "\n")...)
+	newLines = append(newLines, lines[params.EndLine:]...)
+
+	if err := os.WriteFile(full, []byte(strings.Join(newLines, "\n")), 0o644); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("replaced lines %d-%d of %s", params.StartLine, params.EndLine, params.Path), nil
+}
+
+// ListDirTool lists the entries of a directory.
+type ListDirTool struct{}
+
+func (ListDirTool) Name() string { return "list_dir" }
+
+func (ListDirTool) Schema() string {
+	return `{"description":"List the entries of a directory.","parameters":{"type":"object","properties":{"path":{"type":"string","description":"Path relative to the current working directory; defaults to the working directory itself"}}}}`
+}
+
+func (ListDirTool) Run(ctx context.Context, args string) (string, error) {
+	var params struct {
+		Path string `json:"path"`
+	}
+	if args != "" {
+		if err := json.Unmarshal([]byte(args), &params); err != nil {
+			return "", err
+		}
+	}
+	if params.Path == "" {
+		params.Path = "."
+	}
+
+	full, err := resolvePath(params.Path)
+	if err != nil {
+		return "", err
+	}
+	entries, err := os.ReadDir(full)
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() {
+			name += "/"
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, "\n"), nil
+}
@@ -0,0 +1,50 @@
+// Package tools implements cog's built-in toolbox: the functions an agent
+// is allowed to call mid-conversation, such as reading or editing a file.
+package tools
+
+import "context"
+
+// Tool is anything an agent can call during a tool-calling turn. Schema
+// returns a JSON object of the form {"description": "...", "parameters":
+// <JSON Schema for the arguments>}, the shape Backend.Chat needs to build a
+// provider's function definition.
+type Tool interface {
+	Name() string
+	Schema() string
+	Run(ctx context.Context, args string) (string, error)
+}
+
+// Registry resolves a Tool by name, scoped down to whatever subset an
+// agent's allowlist permits.
+type Registry struct {
+	tools map[string]Tool
+}
+
+// NewRegistry creates an empty tool registry.
+func NewRegistry() *Registry {
+	return &Registry{tools: make(map[string]Tool)}
+}
+
+// Register adds t to the registry under t.Name().
+func (r *Registry) Register(t Tool) {
+	r.tools[t.Name()] = t
+}
+
+// Get looks up a tool by name.
+func (r *Registry) Get(name string) (Tool, bool) {
+	t, ok := r.tools[name]
+	return t, ok
+}
+
+// Allowed returns the registered tools named in allowlist, in allowlist's
+// order. An empty allowlist returns no tools — agents opt into tools
+// explicitly rather than getting all of them by default.
+func (r *Registry) Allowed(allowlist []string) []Tool {
+	var allowed []Tool
+	for _, name := range allowlist {
+		if t, ok := r.tools[name]; ok {
+			allowed = append(allowed, t)
+		}
+	}
+	return allowed
+}
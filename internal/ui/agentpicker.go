@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"fmt"
+
+	"cog/internal/agents"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// agentItem adapts an agents.Agent to list.Item so it can be rendered by
+// the same bubbles/list widget the sidebar and model picker use.
+type agentItem agents.Agent
+
+func (i agentItem) FilterValue() string { return i.Name }
+func (i agentItem) Title() string       { return i.Name }
+func (i agentItem) Description() string {
+	if len(i.Tools) == 0 {
+		return "no tools"
+	}
+	return fmt.Sprintf("tools: %v", i.Tools)
+}
+
+// selectAgent applies the agent picker's current selection to the active
+// conversation and persists it.
+func (m *Model) selectAgent() {
+	selected, ok := m.agentPickerList.SelectedItem().(agentItem)
+	if !ok {
+		return
+	}
+
+	for i := range m.conversations {
+		if m.conversations[i].ID != m.currentConvID {
+			continue
+		}
+		m.conversations[i].Agent = selected.Name
+		if err := m.db.SaveConversation(m.conversations[i]); err != nil {
+			m.err = fmt.Errorf("failed to save conversation: %v", err)
+		}
+		break
+	}
+}
+
+// currentAgent looks up the agents.Agent backing the current conversation,
+// falling back to the built-in default.
+func (m *Model) currentAgent() agents.Agent {
+	conv := m.getCurrentConversation()
+	if conv == nil {
+		return agents.Default()
+	}
+	return agents.Find(m.agentList, conv.Agent)
+}
+
+// updateAgentPicker forwards msg to the agent list while the picker has
+// focus, handling Enter (select) and Esc (cancel) itself.
+func (m Model) updateAgentPicker(msg tea.Msg) (Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.Type {
+		case tea.KeyEsc:
+			m.focus = FocusChat
+			return m, nil
+		case tea.KeyEnter:
+			m.selectAgent()
+			m.focus = FocusChat
+			m.updateViewport()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.agentPickerList, cmd = m.agentPickerList.Update(msg)
+	return m, cmd
+}
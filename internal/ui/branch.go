@@ -0,0 +1,135 @@
+package ui
+
+import "cog/internal/models"
+
+// refreshBranchInfo recomputes the sibling position of the current
+// conversation's leaf message (e.g. "[2/3]") so the viewport can render it.
+// branchTotal is left at 0 when the leaf has no siblings, which
+// updateViewport treats as "don't show an indicator".
+func (m *Model) refreshBranchInfo() {
+	m.branchPosition, m.branchTotal = 0, 0
+
+	conv := m.getCurrentConversation()
+	if conv == nil || len(conv.Messages) == 0 {
+		return
+	}
+
+	leaf := conv.Messages[len(conv.Messages)-1]
+	if leaf.ID == 0 {
+		return
+	}
+
+	ids, err := m.db.Siblings(leaf.ID)
+	if err != nil || len(ids) < 2 {
+		return
+	}
+	for i, id := range ids {
+		if id == leaf.ID {
+			m.branchPosition = i + 1
+			m.branchTotal = len(ids)
+			return
+		}
+	}
+}
+
+// cycleBranch moves the active path to the previous (-1) or next (+1)
+// sibling of the current conversation's leaf message and reloads the path
+// from the database.
+func (m *Model) cycleBranch(direction int) {
+	conv := m.getCurrentConversation()
+	if conv == nil || len(conv.Messages) == 0 {
+		return
+	}
+
+	leaf := conv.Messages[len(conv.Messages)-1]
+	if leaf.ID == 0 {
+		return
+	}
+
+	ids, err := m.db.Siblings(leaf.ID)
+	if err != nil || len(ids) < 2 {
+		return
+	}
+
+	pos := 0
+	for i, id := range ids {
+		if id == leaf.ID {
+			pos = i
+			break
+		}
+	}
+	next := (pos + direction + len(ids)) % len(ids)
+	if err := m.db.SwitchBranch(ids[next]); err != nil {
+		m.err = err
+		return
+	}
+
+	path, err := m.db.LoadMessagePath(conv.ID)
+	if err != nil {
+		m.err = err
+		return
+	}
+	for i := range m.conversations {
+		if m.conversations[i].ID == conv.ID {
+			m.conversations[i].Messages = path
+			break
+		}
+	}
+	m.refreshBranchInfo()
+}
+
+// beginEditMessage loads msg into the textarea for editing. Submitting it
+// calls CreateBranch instead of appending a new leaf, so the original reply
+// survives as a side branch the user can switch back to.
+func (m *Model) beginEditMessage(msg models.Message) {
+	m.editingMessageID = msg.ID
+	m.textarea.SetValue(msg.Content)
+	m.textarea.Focus()
+	m.focus = FocusChat
+}
+
+// submitBranchEdit replaces the message queued by beginEditMessage with
+// userMsg as a new sibling branch, truncating everything that came after it
+// in the active path (that subtree is still in the database, just no longer
+// selected).
+func (m *Model) submitBranchEdit(userMsg models.Message) {
+	editingID := m.editingMessageID
+	m.editingMessageID = 0
+
+	for i := range m.conversations {
+		if m.conversations[i].ID != m.currentConvID {
+			continue
+		}
+
+		branched, err := m.db.CreateBranch(m.currentConvID, editingID, userMsg)
+		if err != nil {
+			m.err = err
+			return
+		}
+
+		cut := len(m.conversations[i].Messages)
+		for idx, msg := range m.conversations[i].Messages {
+			if msg.ID == editingID {
+				cut = idx
+				break
+			}
+		}
+		m.conversations[i].Messages = append(m.conversations[i].Messages[:cut], branched)
+		return
+	}
+}
+
+// lastUserMessage returns the most recent user message in the current
+// conversation, or nil if there isn't one yet.
+func (m *Model) lastUserMessage() *models.Message {
+	conv := m.getCurrentConversation()
+	if conv == nil {
+		return nil
+	}
+	for i := len(conv.Messages) - 1; i >= 0; i-- {
+		if conv.Messages[i].Role == "user" {
+			return &conv.Messages[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editorTarget records what a $EDITOR round-trip (see openMessageEditor) is
+// editing, so the msgEditorDone that comes back knows where to apply it.
+type editorTarget int
+
+const (
+	editorTargetNone editorTarget = iota
+	editorTargetMessage
+	editorTargetTextarea
+)
+
+// msgEditorDone carries $EDITOR's result once tea.ExecProcess returns
+// control to the program: either the edited text, or err if the process
+// failed to run or its tempfile couldn't be read back.
+type msgEditorDone struct {
+	target  editorTarget
+	content string
+	err     error
+}
+
+// toggleMessageNav switches FocusChat between typing a new message and
+// navigating the conversation's history with j/k. Entering nav mode starts
+// the cursor on the current leaf; leaving it refocuses the textarea.
+func (m *Model) toggleMessageNav() {
+	if m.textarea.Focused() {
+		conv := m.getCurrentConversation()
+		if conv == nil || len(conv.Messages) == 0 {
+			return
+		}
+		m.textarea.Blur()
+		m.selectedMessage = len(conv.Messages) - 1
+	} else {
+		m.selectedMessage = -1
+		m.textarea.Focus()
+	}
+	m.updateViewport()
+}
+
+// moveMessageSelection shifts selectedMessage by delta, clamped to the
+// current conversation's message bounds.
+func (m *Model) moveMessageSelection(delta int) {
+	conv := m.getCurrentConversation()
+	if conv == nil || len(conv.Messages) == 0 {
+		return
+	}
+	next := m.selectedMessage + delta
+	if next < 0 {
+		next = 0
+	}
+	if next > len(conv.Messages)-1 {
+		next = len(conv.Messages) - 1
+	}
+	m.selectedMessage = next
+	m.updateViewport()
+	m.scrollViewportToMessage(conv.Messages[next].ID)
+}
+
+// exitMessageSelection leaves nav mode and returns focus to the textarea.
+func (m *Model) exitMessageSelection() {
+	m.selectedMessage = -1
+	m.textarea.Focus()
+}
+
+// openMessageEditor writes the selected message's content (or the textarea's
+// current draft, if nothing is selected) to a tempfile and opens it in
+// $EDITOR, falling back to vi. The result comes back as a msgEditorDone once
+// the external process exits.
+func (m *Model) openMessageEditor() tea.Cmd {
+	var content string
+	if m.selectedMessage >= 0 {
+		conv := m.getCurrentConversation()
+		if conv == nil || m.selectedMessage >= len(conv.Messages) {
+			return nil
+		}
+		content = conv.Messages[m.selectedMessage].Content
+		m.editorTarget = editorTargetMessage
+	} else {
+		content = m.textarea.Value()
+		m.editorTarget = editorTargetTextarea
+	}
+
+	tmp, err := os.CreateTemp("", "cog-edit-*.md")
+	if err != nil {
+		m.err = err
+		return nil
+	}
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		m.err = err
+		return nil
+	}
+	tmp.Close()
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	target := m.editorTarget
+
+	cmd := exec.Command(editor, tmp.Name())
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		defer os.Remove(tmp.Name())
+		if err != nil {
+			return msgEditorDone{target: target, err: err}
+		}
+		data, readErr := os.ReadFile(tmp.Name())
+		if readErr != nil {
+			return msgEditorDone{target: target, err: readErr}
+		}
+		return msgEditorDone{target: target, content: string(data)}
+	})
+}
+
+// applyEditedMessage overwrites the selected message's Content with the
+// $EDITOR result and re-saves the conversation. SaveConversation updates an
+// already-persisted message's row in place rather than branching, since this
+// is a direct correction rather than a retry or a branch-on-edit.
+func (m *Model) applyEditedMessage(content string) {
+	if m.selectedMessage < 0 {
+		return
+	}
+	for i := range m.conversations {
+		if m.conversations[i].ID != m.currentConvID {
+			continue
+		}
+		if m.selectedMessage >= len(m.conversations[i].Messages) {
+			return
+		}
+		edited := &m.conversations[i].Messages[m.selectedMessage]
+		edited.Content = strings.TrimRight(content, "\n")
+		if err := m.db.SaveConversation(m.conversations[i]); err != nil {
+			m.err = fmt.Errorf("failed to save conversation: %v", err)
+		}
+		m.invalidateMessageCache(edited.ID)
+		break
+	}
+	m.updateViewport()
+}
+
+// retrySelectedMessage discards the selected assistant message (and
+// whatever followed it) and re-invokes the backend on the user turn that
+// preceded it. The new reply lands as finishStream's ordinary append, which
+// SaveConversation inserts as a fresh branch off that user message.
+//
+// The discarded branch is detached from the database synchronously, before
+// beginStreamingReply does any async work — same as submitBranchEdit and
+// applyEditedMessage. Otherwise, cancelling (or the app exiting) before the
+// first token of the retry arrives would leave the old reply's
+// selected_child_id link fully intact, and reloading the conversation would
+// silently resurrect the reply the user just retried away.
+func (m *Model) retrySelectedMessage() tea.Cmd {
+	conv := m.getCurrentConversation()
+	if conv == nil || m.selectedMessage < 0 || m.selectedMessage >= len(conv.Messages) {
+		return nil
+	}
+	if conv.Messages[m.selectedMessage].Role != "assistant" {
+		return nil
+	}
+
+	cut := m.selectedMessage
+	if cut > 0 {
+		if err := m.db.ClearSelectedChild(conv.Messages[cut-1].ID); err != nil {
+			m.err = fmt.Errorf("failed to detach retried reply: %v", err)
+			return nil
+		}
+	}
+
+	for i := range m.conversations {
+		if m.conversations[i].ID != m.currentConvID {
+			continue
+		}
+		m.conversations[i].Messages = m.conversations[i].Messages[:cut]
+		break
+	}
+
+	m.exitMessageSelection()
+	return m.beginStreamingReply()
+}
+
+// continueLastReply asks the model to extend its last assistant message
+// rather than start a new turn; finishStream appends the new content onto
+// that message instead of creating a sibling one (see Model.continuation).
+func (m *Model) continueLastReply() tea.Cmd {
+	conv := m.getCurrentConversation()
+	if conv == nil || len(conv.Messages) == 0 {
+		return nil
+	}
+	if conv.Messages[len(conv.Messages)-1].Role != "assistant" {
+		return nil
+	}
+
+	m.continuation = true
+	m.exitMessageSelection()
+	return m.beginStreamingReply()
+}
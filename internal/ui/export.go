@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cog/internal/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v2"
+)
+
+// exportsDir returns the default directory conversation exports are written
+// under, ~/.local/share/cog/exports — a different base than ~/.cog, since
+// exports are user-facing output rather than cog's own config/state.
+func exportsDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "cog", "exports"), nil
+}
+
+// beginExportConversation opens the export path overlay for the sidebar's
+// currently selected conversation, pre-filled with a default path under
+// exportsDir named after the conversation's ID.
+func (m *Model) beginExportConversation() {
+	conv := m.selectedSidebarConversation()
+	if conv == nil {
+		return
+	}
+
+	dir, err := exportsDir()
+	if err != nil {
+		m.err = fmt.Errorf("failed to resolve export directory: %v", err)
+		return
+	}
+
+	m.exportingConvID = conv.ID
+	m.exportPathInput.SetValue(filepath.Join(dir, conv.ID))
+	m.exportPathInput.CursorEnd()
+	m.exportPathInput.Focus()
+	m.focus = FocusExportPath
+}
+
+// applyExport writes m.exportingConvID to path+".yaml" (a full YAML dump of
+// its models.Conversation) and path+".md" (a Markdown transcript), creating
+// path's parent directory if needed.
+func (m *Model) applyExport(path string) {
+	if path == "" || m.exportingConvID == "" {
+		return
+	}
+	conv := m.findConversation(m.exportingConvID)
+	if conv == nil {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		m.err = fmt.Errorf("failed to create export directory: %v", err)
+		return
+	}
+
+	yamlData, err := yaml.Marshal(conv)
+	if err != nil {
+		m.err = fmt.Errorf("failed to marshal conversation: %v", err)
+		return
+	}
+	if err := os.WriteFile(path+".yaml", yamlData, 0644); err != nil {
+		m.err = fmt.Errorf("failed to write %s: %v", path+".yaml", err)
+		return
+	}
+
+	if err := os.WriteFile(path+".md", []byte(exportMarkdown(*conv)), 0644); err != nil {
+		m.err = fmt.Errorf("failed to write %s: %v", path+".md", err)
+		return
+	}
+}
+
+// exportMarkdown renders conv as a Markdown transcript: one role header per
+// message, followed by its content verbatim (an assistant reply's own
+// fenced code blocks, if any, come along as-is).
+func exportMarkdown(conv models.Conversation) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", conv.Name)
+	for _, msg := range conv.Messages {
+		fmt.Fprintf(&b, "### %s\n\n%s\n\n", capitalize(msg.Role), msg.Content)
+	}
+	return b.String()
+}
+
+// capitalize upper-cases role's first rune for use as a Markdown heading,
+// e.g. "assistant" -> "Assistant".
+func capitalize(role string) string {
+	if role == "" {
+		return role
+	}
+	return strings.ToUpper(role[:1]) + role[1:]
+}
+
+// updateExportPath forwards msg to the export path input, handling Enter
+// (write the export and return to the sidebar) and Esc (cancel) itself.
+func (m Model) updateExportPath(msg tea.Msg) (Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.Type {
+		case tea.KeyEsc:
+			m.exportingConvID = ""
+			m.exportPathInput.Blur()
+			m.focus = FocusSidebar
+			return m, nil
+		case tea.KeyEnter:
+			m.applyExport(strings.TrimSpace(m.exportPathInput.Value()))
+			m.exportingConvID = ""
+			m.exportPathInput.Blur()
+			m.focus = FocusSidebar
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.exportPathInput, cmd = m.exportPathInput.Update(msg)
+	return m, cmd
+}
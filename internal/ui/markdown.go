@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"cog/internal/models"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/muesli/reflow/wordwrap"
+	"github.com/muesli/reflow/wrap"
+)
+
+// markdownRenderer returns a glamour renderer matching the viewport's current
+// width, word-wrap setting, and configured style, rebuilding it only when one
+// of those has changed since the last call.
+func (m *Model) markdownRenderer() (*glamour.TermRenderer, error) {
+	width := m.viewport.Width
+	if !m.wordWrap {
+		width = 0
+	}
+
+	if m.glamourRenderer != nil && m.glamourRendererWidth == width && m.glamourRendererStyle == m.glamourStyle {
+		return m.glamourRenderer, nil
+	}
+
+	opts := []glamour.TermRendererOption{glamour.WithWordWrap(width)}
+	switch m.glamourStyle {
+	case "", "dark":
+		opts = append(opts, glamour.WithStandardStyle("dark"))
+	case "light", "notty":
+		opts = append(opts, glamour.WithStandardStyle(m.glamourStyle))
+	default:
+		opts = append(opts, glamour.WithStylePath(m.glamourStyle))
+	}
+
+	r, err := glamour.NewTermRenderer(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	m.glamourRenderer = r
+	m.glamourRendererWidth = width
+	m.glamourRendererStyle = m.glamourStyle
+	return r, nil
+}
+
+// renderMarkdown runs content through glamour, falling back to the raw text
+// if the renderer fails to build or the content doesn't parse — a malformed
+// code fence shouldn't take down the whole viewport.
+func (m *Model) renderMarkdown(content string) string {
+	r, err := m.markdownRenderer()
+	if err != nil {
+		return content
+	}
+	out, err := r.Render(content)
+	if err != nil {
+		return content
+	}
+	return out
+}
+
+// wrapRaw hard-wraps content to the viewport's width for "raw" display mode
+// (see m.rawMode): wordwrap breaks at word boundaries first, then wrap
+// forces a break on whatever's left over (a single word wider than width),
+// the usual muesli/reflow pairing.
+func (m *Model) wrapRaw(content string) string {
+	width := m.viewport.Width
+	if width <= 0 {
+		return content
+	}
+	return wrap.String(wordwrap.String(content, width), width)
+}
+
+// renderedMessageContent returns msg's display content — Glamour-rendered
+// markdown normally, or word-wrapped raw text while m.rawMode is toggled on
+// (see Ctrl+R) — caching it by message ID. A branch edit (Ctrl+E) or a retry
+// adds a new message rather than changing one in place, so those never leave
+// a stale entry under an existing ID; invalidateMessageCacheIfStale clears
+// the cache in bulk when the width,
+// word-wrap setting, style, or raw/rendered mode it was built under changes,
+// and invalidateMessageCache clears a single entry when something mutates a
+// persisted message's Content directly (see applyEditedMessage, and
+// finishStream's continuation append).
+func (m *Model) renderedMessageContent(msg models.Message) string {
+	if m.messageCache == nil {
+		m.messageCache = make(map[int64]string)
+	}
+	if cached, ok := m.messageCache[msg.ID]; ok {
+		return cached
+	}
+
+	var rendered string
+	if m.rawMode {
+		rendered = m.wrapRaw(msg.Content)
+	} else {
+		rendered = m.renderMarkdown(msg.Content)
+	}
+	m.messageCache[msg.ID] = rendered
+	return rendered
+}
+
+// invalidateMessageCache drops a single message's cached rendering, for a
+// direct content mutation that doesn't go through CreateBranch.
+func (m *Model) invalidateMessageCache(id int64) {
+	if m.messageCache != nil {
+		delete(m.messageCache, id)
+	}
+}
+
+// invalidateMessageCacheIfStale clears the whole rendered-content cache once
+// the viewport width, word-wrap setting, style, or raw/rendered mode has
+// moved on from whatever it was last rendered with.
+func (m *Model) invalidateMessageCacheIfStale() {
+	width := m.viewport.Width
+	if m.messageCacheWidth == width && m.messageCacheWrap == m.wordWrap &&
+		m.messageCacheStyle == m.glamourStyle && m.messageCacheRaw == m.rawMode {
+		return
+	}
+	m.messageCache = nil
+	m.messageCacheWidth = width
+	m.messageCacheWrap = m.wordWrap
+	m.messageCacheStyle = m.glamourStyle
+	m.messageCacheRaw = m.rawMode
+	m.glamourRenderer = nil
+}
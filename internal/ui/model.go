@@ -5,17 +5,23 @@ import (
 	"fmt"
 	"strings"
 	"time"
-	"unicode/utf8"
 
+	"cog/internal/agents"
+	"cog/internal/backends"
 	"cog/internal/models"
+	"cog/internal/prompts"
 	"cog/internal/storage"
+	"cog/internal/tools"
 
+	"github.com/charmbracelet/bubbles/cursor"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/sashabaranov/go-openai"
 )
 
 type FocusState int
@@ -23,6 +29,15 @@ type FocusState int
 const (
 	FocusSidebar FocusState = iota
 	FocusChat
+	FocusModelPicker
+	FocusAgentPicker
+	FocusDeleteConfirm
+	FocusRename
+	FocusSearch
+	FocusSystemPrompt
+	FocusPromptLibrary
+	FocusPromptSaveName
+	FocusExportPath
 )
 
 // Model represents the main application state
@@ -32,25 +47,122 @@ type Model struct {
 	conversations   []models.Conversation
 	currentConvID   string
 	convList        list.Model
-	client          *openai.Client
+	registry        *backends.Registry
+	defaultModel    string
+	modelList       list.Model
+	agentList       []agents.Agent
+	defaultAgent    string
+	agentPickerList list.Model
+	toolRegistry    *tools.Registry
+	showToolResults bool
 	db              *storage.Database
-	loading         bool
-	err             error
-	ready           bool
-	focus           FocusState
-	width           int
-	height          int
-	sidebarWidth    int
-}
 
-// ResponseMsg represents a message from the OpenAI API
-type ResponseMsg struct {
-	Content string
-	Err     error
+	// renameInput backs the sidebar's rename overlay; renamingConvID names
+	// the conversation it's currently editing. pendingDeleteID names the
+	// conversation awaiting a y/n answer from the delete confirmation overlay.
+	renameInput     textinput.Model
+	renamingConvID  string
+	pendingDeleteID string
+
+	// searchInput holds the /-search overlay's query; once runSearch has
+	// populated searchResultsList, searching is true and arrow keys navigate
+	// results instead of editing the query.
+	searchInput       textinput.Model
+	searchResultsList list.Model
+	searching         bool
+
+	// systemPromptInput is the full-screen textarea backing FocusSystemPrompt,
+	// editing the current conversation's SystemPrompt override. promptLibrary
+	// holds every saved prompt loaded from disk at startup; promptLibraryList
+	// renders it for FocusPromptLibrary. promptSaveNameInput names the prompt
+	// being saved under FocusPromptSaveName.
+	systemPromptInput   textarea.Model
+	promptLibrary       []prompts.Prompt
+	promptLibraryList   list.Model
+	promptSaveNameInput textinput.Model
+
+	// exportPathInput backs the sidebar's export overlay (FocusExportPath),
+	// pre-filled with a default path under ~/.local/share/cog/exports;
+	// exportingConvID names the conversation it's exporting. See export.go.
+	exportPathInput textinput.Model
+	exportingConvID string
+
+	// messageLineOffsets maps a message ID to the line it starts on in the
+	// viewport's last rendered content, so a search hit can scroll straight
+	// to it instead of just jumping to the conversation.
+	messageLineOffsets map[int64]int
+
+	// messageCache holds each message's rendered display content, keyed by
+	// message ID (see renderedMessageContent); the four fields after it
+	// record what it was last rendered with so a width/wrap/style/mode
+	// change can invalidate it in bulk instead of per message. rawMode
+	// toggles between Glamour-rendered markdown and plain word-wrapped
+	// source (Ctrl+R).
+	messageCache         map[int64]string
+	messageCacheWidth    int
+	messageCacheWrap     bool
+	messageCacheStyle    string
+	messageCacheRaw      bool
+	rawMode              bool
+	wordWrap             bool
+	glamourStyle         string
+	glamourRenderer      *glamour.TermRenderer
+	glamourRendererWidth int
+	glamourRendererStyle string
+
+	loading      bool
+	err          error
+	ready        bool
+	focus        FocusState
+	width        int
+	height       int
+	sidebarWidth int
+
+	// streaming holds the state of an in-flight streamed reply. pendingContent
+	// accumulates tokens as they arrive; streamConvID pins the reply to the
+	// conversation it was started on in case the user switches away mid-stream.
+	streaming      bool
+	cancelling     bool
+	streamConvID   string
+	pendingContent string
+	tokenCount     int
+	startTime      time.Time
+	elapsed        time.Duration
+	replyChan      chan tea.Msg
+	stopSignal     chan struct{}
+	streamCancel   context.CancelFunc
+	spinner        spinner.Model
+	cursor         cursor.Model
+
+	// editingMessageID is non-zero while the textarea holds an earlier
+	// message queued for a branch edit (see beginEditMessage). branchPosition
+	// and branchTotal describe the current leaf's sibling count, e.g. "2 of 3".
+	editingMessageID int64
+	branchPosition   int
+	branchTotal      int
+
+	// selectedMessage is the j/k navigation cursor's index into the current
+	// conversation's Messages, or -1 when the textarea has focus instead (see
+	// editor.go). editorTarget records what openMessageEditor last sent to
+	// $EDITOR, so the returning msgEditorDone knows where to apply the
+	// result. continuation is set while a stream is asking the model to
+	// extend its last reply rather than start a new one (see
+	// continueLastReply); finishStream appends instead of creating a new
+	// assistant message while it's true.
+	selectedMessage int
+	editorTarget    editorTarget
+	continuation    bool
 }
 
-// NewModel creates a new UI model
-func NewModel(client *openai.Client, db *storage.Database) *Model {
+// NewModel creates a new UI model. registry resolves a conversation's
+// Backend field to the provider that should serve it; defaultBackend and
+// defaultModel are what new conversations are created with. agentList is
+// every agent loaded from ~/.cog/agents, and defaultAgent names the one new
+// conversations start with. toolRegistry holds every tool cog knows how to
+// run; an agent's Tools allowlist decides which of them it can reach.
+// markdownStyle is the glamour style ("dark", "light", "notty", or a path to
+// a custom style JSON) used to render assistant messages.
+func NewModel(registry *backends.Registry, db *storage.Database, defaultBackend, defaultModel string, agentList []agents.Agent, defaultAgent string, toolRegistry *tools.Registry, markdownStyle string) *Model {
 	ta := textarea.New()
 	ta.Placeholder = "Type your message..."
 	ta.Prompt = "┃ "
@@ -72,7 +184,7 @@ func NewModel(client *openai.Client, db *storage.Database) *Model {
 
 	// If no conversations exist, create a default one
 	if len(conversations) == 0 {
-		initialConv := NewConversation("New Chat")
+		initialConv := NewConversation("New Chat", defaultBackend, defaultModel, defaultAgent)
 		conversations = []models.Conversation{initialConv}
 		if err := db.SaveConversation(initialConv); err != nil {
 			// Handle error - could be logged
@@ -91,31 +203,116 @@ func NewModel(client *openai.Client, db *storage.Database) *Model {
 	convList.SetFilteringEnabled(false)
 	convList.SetShowHelp(false)
 
+	modelList := list.New(nil, list.NewDefaultDelegate(), 30, 20)
+	modelList.Title = "Models"
+	modelList.SetShowStatusBar(false)
+	modelList.SetFilteringEnabled(false)
+	modelList.SetShowHelp(false)
+
+	agentItems := make([]list.Item, len(agentList))
+	for i, a := range agentList {
+		agentItems[i] = agentItem(a)
+	}
+	agentPickerList := list.New(agentItems, list.NewDefaultDelegate(), 30, 20)
+	agentPickerList.Title = "Agents"
+	agentPickerList.SetShowStatusBar(false)
+	agentPickerList.SetFilteringEnabled(false)
+	agentPickerList.SetShowHelp(false)
+
+	renameInput := textinput.New()
+	renameInput.Placeholder = "New name"
+	renameInput.CharLimit = 60
+
+	searchInput := textinput.New()
+	searchInput.Placeholder = "Search all conversations..."
+	searchInput.CharLimit = 200
+
+	searchResultsList := list.New(nil, list.NewDefaultDelegate(), 30, 20)
+	searchResultsList.Title = "Search"
+	searchResultsList.SetShowStatusBar(false)
+	searchResultsList.SetFilteringEnabled(false)
+	searchResultsList.SetShowHelp(false)
+
+	systemPromptInput := textarea.New()
+	systemPromptInput.Placeholder = "System prompt for this conversation..."
+	systemPromptInput.ShowLineNumbers = false
+
+	promptLibrary, err := prompts.LoadAll()
+	if err != nil {
+		promptLibrary = nil
+	}
+	promptItems := make([]list.Item, len(promptLibrary))
+	for i, p := range promptLibrary {
+		promptItems[i] = promptItem(p)
+	}
+	promptLibraryList := list.New(promptItems, list.NewDefaultDelegate(), 30, 20)
+	promptLibraryList.Title = "Prompt Library"
+	promptLibraryList.SetShowStatusBar(false)
+	promptLibraryList.SetFilteringEnabled(false)
+	promptLibraryList.SetShowHelp(false)
+
+	promptSaveNameInput := textinput.New()
+	promptSaveNameInput.Placeholder = "Save prompt as..."
+	promptSaveNameInput.CharLimit = 60
+
+	exportPathInput := textinput.New()
+	exportPathInput.Placeholder = "Export path (no extension)"
+	exportPathInput.CharLimit = 200
+
 	// Set current conversation to the first one
 	var currentConvID string
 	if len(conversations) > 0 {
 		currentConvID = conversations[0].ID
 	}
 
-	return &Model{
-		textarea:      ta,
-		viewport:      vp,
-		conversations: conversations,
-		currentConvID: currentConvID,
-		convList:      convList,
-		client:        client,
-		db:            db,
-		loading:       false,
-		err:           nil,
-		ready:         false,
-		focus:         FocusChat,
-		sidebarWidth:  30,
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+	sp.Style = LoadingStyle
+
+	cur := cursor.New()
+	cur.SetChar("▌")
+	cur.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4"))
+
+	m := &Model{
+		textarea:            ta,
+		viewport:            vp,
+		conversations:       conversations,
+		currentConvID:       currentConvID,
+		convList:            convList,
+		registry:            registry,
+		defaultModel:        defaultModel,
+		modelList:           modelList,
+		agentList:           agentList,
+		defaultAgent:        defaultAgent,
+		agentPickerList:     agentPickerList,
+		toolRegistry:        toolRegistry,
+		renameInput:         renameInput,
+		searchInput:         searchInput,
+		searchResultsList:   searchResultsList,
+		systemPromptInput:   systemPromptInput,
+		promptLibrary:       promptLibrary,
+		promptLibraryList:   promptLibraryList,
+		promptSaveNameInput: promptSaveNameInput,
+		exportPathInput:     exportPathInput,
+		db:                  db,
+		loading:             false,
+		err:                 nil,
+		ready:               false,
+		focus:               FocusChat,
+		sidebarWidth:        30,
+		spinner:             sp,
+		cursor:              cur,
+		wordWrap:            true,
+		glamourStyle:        markdownStyle,
+		selectedMessage:     -1,
 	}
+	m.refreshBranchInfo()
+	return m
 }
 
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(textarea.Blink, tea.EnterAltScreen)
+	return tea.Batch(textarea.Blink, tea.EnterAltScreen, m.spinner.Tick)
 }
 
 // GenerateConvID generates a unique conversation ID
@@ -123,13 +320,27 @@ func GenerateConvID() string {
 	return fmt.Sprintf("conv_%d", time.Now().Unix())
 }
 
-// NewConversation creates a new conversation
-func NewConversation(title string) models.Conversation {
+// NewConversation creates a new conversation pinned to the given backend,
+// model, and agent.
+func NewConversation(title, backend, model, agent string) models.Conversation {
 	return models.Conversation{
 		ID:       GenerateConvID(),
 		Name:     title,
 		Messages: []models.Message{},
 		Created:  time.Now(),
+		Backend:  backend,
+		Model:    model,
+		Agent:    agent,
+	}
+}
+
+// newAssistantMessage wraps content in an assistant Message stamped with the
+// current time.
+func (m *Model) newAssistantMessage(content string) models.Message {
+	return models.Message{
+		Role:    "assistant",
+		Content: content,
+		Time:    time.Now(),
 	}
 }
 
@@ -152,19 +363,168 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.viewport = viewport.New(chatWidth, chatHeight)
 			m.textarea.SetWidth(chatWidth - 2)
 			m.convList.SetSize(m.sidebarWidth-2, chatHeight+3)
+			m.modelList.SetSize(m.sidebarWidth-2, chatHeight+3)
 			m.ready = true
 		} else {
 			m.viewport.Width = chatWidth
 			m.viewport.Height = chatHeight
 			m.textarea.SetWidth(chatWidth - 2)
 			m.convList.SetSize(m.sidebarWidth-2, chatHeight+3)
+			m.modelList.SetSize(m.sidebarWidth-2, chatHeight+3)
 		}
 		m.updateViewport()
 
 	case tea.KeyMsg:
+		if m.focus == FocusModelPicker {
+			return m.updateModelPicker(msg)
+		}
+		if m.focus == FocusAgentPicker {
+			return m.updateAgentPicker(msg)
+		}
+		if m.focus == FocusDeleteConfirm {
+			return m.updateDeleteConfirm(msg)
+		}
+		if m.focus == FocusRename {
+			return m.updateRenameInput(msg)
+		}
+		if m.focus == FocusSearch {
+			return m.updateSearch(msg)
+		}
+		if m.focus == FocusSystemPrompt {
+			return m.updateSystemPrompt(msg)
+		}
+		if m.focus == FocusPromptLibrary {
+			return m.updatePromptLibrary(msg)
+		}
+		if m.focus == FocusPromptSaveName {
+			return m.updatePromptSaveName(msg)
+		}
+		if m.focus == FocusExportPath {
+			return m.updateExportPath(msg)
+		}
 		switch msg.Type {
-		case tea.KeyCtrlC, tea.KeyEsc:
+		case tea.KeyEsc:
+			return m, tea.Quit
+		case tea.KeyCtrlC:
+			if m.loading && m.stopSignal != nil {
+				m.cancelling = true
+				close(m.stopSignal)
+				m.stopSignal = nil
+				return m, nil
+			}
 			return m, tea.Quit
+		case tea.KeyCtrlB:
+			if !m.loading {
+				m.openModelPicker()
+			}
+		case tea.KeyCtrlA:
+			if !m.loading {
+				m.focus = FocusAgentPicker
+			}
+		case tea.KeyCtrlX:
+			if m.streaming && m.stopSignal != nil {
+				m.cancelling = true
+				close(m.stopSignal)
+				m.stopSignal = nil
+			}
+		case tea.KeyCtrlT:
+			m.showToolResults = !m.showToolResults
+			m.updateViewport()
+		case tea.KeyCtrlW:
+			m.wordWrap = !m.wordWrap
+			m.updateViewport()
+		case tea.KeyCtrlR:
+			m.rawMode = !m.rawMode
+			m.updateViewport()
+		case tea.KeyCtrlE:
+			if m.focus == FocusChat && !m.loading {
+				if last := m.lastUserMessage(); last != nil {
+					m.beginEditMessage(*last)
+				}
+			}
+		case tea.KeyCtrlS:
+			if !m.loading {
+				m.openSystemPromptEditor()
+			}
+		case tea.KeyCtrlP:
+			if !m.loading {
+				m.openPromptLibrary()
+			}
+		case tea.KeyCtrlK:
+			if m.focus == FocusChat && !m.loading {
+				m.toggleMessageNav()
+			}
+		case tea.KeyUp:
+			if m.focus == FocusChat && !m.textarea.Focused() && !m.loading {
+				m.moveMessageSelection(-1)
+				return m, nil
+			}
+		case tea.KeyDown:
+			if m.focus == FocusChat && !m.textarea.Focused() && !m.loading {
+				m.moveMessageSelection(1)
+				return m, nil
+			}
+		case tea.KeyRunes:
+			if len(msg.Runes) == 1 && msg.Runes[0] == '/' && !m.loading &&
+				(m.focus == FocusSidebar || (m.focus == FocusChat && strings.TrimSpace(m.textarea.Value()) == "")) {
+				m.openSearch()
+				return m, nil
+			}
+			if m.focus == FocusSidebar && len(msg.Runes) == 1 {
+				switch msg.Runes[0] {
+				case 'n':
+					m.createNewConversation()
+					return m, nil
+				case 'd':
+					m.beginDeleteConversation()
+					return m, nil
+				case 'r':
+					m.beginRenameConversation()
+					return m, nil
+				case 'R':
+					if conv := m.selectedSidebarConversation(); conv != nil {
+						return m, m.GenerateTitle(conv.ID)
+					}
+					return m, nil
+				case 'x':
+					m.beginExportConversation()
+					return m, nil
+				}
+			}
+			if m.focus == FocusChat && !m.loading && strings.TrimSpace(m.textarea.Value()) == "" && len(msg.Runes) == 1 {
+				switch msg.Runes[0] {
+				case '[':
+					m.cycleBranch(-1)
+					m.updateViewport()
+					return m, nil
+				case ']':
+					m.cycleBranch(1)
+					m.updateViewport()
+					return m, nil
+				}
+			}
+			// Message-navigation mode: the textarea is blurred (see
+			// toggleMessageNav, bound to Ctrl+K) and j/k/arrows move
+			// selectedMessage instead of typing.
+			if m.focus == FocusChat && !m.textarea.Focused() && !m.loading && len(msg.Runes) == 1 {
+				switch msg.Runes[0] {
+				case 'j':
+					m.moveMessageSelection(1)
+					return m, nil
+				case 'k':
+					m.moveMessageSelection(-1)
+					return m, nil
+				case 'i':
+					m.toggleMessageNav()
+					return m, nil
+				case 'e':
+					return m, m.openMessageEditor()
+				case 'r':
+					return m, m.retrySelectedMessage()
+				case 'c':
+					return m, m.continueLastReply()
+				}
+			}
 		case tea.KeyTab:
 			if m.focus == FocusSidebar {
 				m.focus = FocusChat
@@ -174,25 +534,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.textarea.Blur()
 			}
 		case tea.KeyCtrlN:
-			// Create new conversation
-			newConv := NewConversation("New Chat")
-			m.conversations = append(m.conversations, newConv)
-			m.currentConvID = newConv.ID
-			
-			// Save to database
-			if err := m.db.SaveConversation(newConv); err != nil {
-				m.err = fmt.Errorf("failed to save conversation: %v", err)
-			}
-			
-			m.updateConversationList()
-			m.updateViewport()
-			m.focus = FocusChat
-			m.textarea.Focus()
+			m.createNewConversation()
 		case tea.KeyEnter:
 			if m.focus == FocusSidebar {
 				// Switch to selected conversation
 				if selectedItem, ok := m.convList.SelectedItem().(models.Conversation); ok {
 					m.currentConvID = selectedItem.ID
+					m.refreshBranchInfo()
 					m.updateViewport()
 					m.focus = FocusChat
 					m.textarea.Focus()
@@ -204,61 +552,106 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					Content: strings.TrimSpace(m.textarea.Value()),
 					Time:    time.Now(),
 				}
-				
-				// Add message to current conversation
-				for i := range m.conversations {
-					if m.conversations[i].ID == m.currentConvID {
-						m.conversations[i].Messages = append(m.conversations[i].Messages, userMsg)
-						// Update conversation title if it's the first message
-						if len(m.conversations[i].Messages) == 1 {
-							title := userMsg.Content
-							if utf8.RuneCountInString(title) > 30 {
-								title = string([]rune(title)[:27]) + "..."
+
+				var titleCmd tea.Cmd
+				if m.editingMessageID != 0 {
+					m.submitBranchEdit(userMsg)
+				} else {
+					// Add message to current conversation
+					for i := range m.conversations {
+						if m.conversations[i].ID == m.currentConvID {
+							m.conversations[i].Messages = append(m.conversations[i].Messages, userMsg)
+							if len(m.conversations[i].Messages) == 1 {
+								titleCmd = m.GenerateTitle(m.conversations[i].ID)
 							}
-							m.conversations[i].Name = title
-						}
-						
-						// Save to database
-						if err := m.db.SaveConversation(m.conversations[i]); err != nil {
-							m.err = fmt.Errorf("failed to save conversation: %v", err)
+
+							// Save to database
+							if err := m.db.SaveConversation(m.conversations[i]); err != nil {
+								m.err = fmt.Errorf("failed to save conversation: %v", err)
+							}
+							break
 						}
-						break
 					}
 				}
-				
-				m.loading = true
+
 				m.textarea.Reset()
-				m.updateConversationList()
-				m.updateViewport()
-				return m, m.sendMessage(userMsg.Content)
+				cmd := m.beginStreamingReply()
+				if titleCmd != nil {
+					return m, tea.Batch(cmd, titleCmd)
+				}
+				return m, cmd
 			}
 		}
 
-	case ResponseMsg:
-		m.loading = false
-		if msg.Err != nil {
-			m.err = msg.Err
-		} else {
-			assistantMsg := models.Message{
-				Role:    "assistant",
-				Content: msg.Content,
-				Time:    time.Now(),
-			}
-			// Add response to current conversation
-			for i := range m.conversations {
-				if m.conversations[i].ID == m.currentConvID {
-					m.conversations[i].Messages = append(m.conversations[i].Messages, assistantMsg)
-					
-					// Save to database
-					if err := m.db.SaveConversation(m.conversations[i]); err != nil {
-						m.err = fmt.Errorf("failed to save conversation: %v", err)
-					}
-					break
-				}
-			}
+	case msgResponseChunk:
+		m.pendingContent += msg.content
+		m.tokenCount++
+		m.updateViewport()
+		return m, waitForStreamEvent(m.replyChan)
+
+	case msgResponseDone:
+		if msg.err != nil {
+			m.err = fmt.Errorf("failed to generate response: %v", msg.err)
+			m.finishStream()
+			m.updateConversationList()
+			m.updateViewport()
+			return m, nil
+		}
+		if !msg.cancelled && len(msg.toolCalls) > 0 {
+			cmd := m.startToolCalls(msg.toolCalls)
+			m.updateConversationList()
+			m.updateViewport()
+			return m, tea.Batch(cmd, m.spinner.Tick)
+		}
+		m.finishStream()
+		m.updateConversationList()
+		m.updateViewport()
+
+	case msgToolCallsDone:
+		if msg.cancelled {
+			m.finishStream()
+			m.updateConversationList()
+			m.updateViewport()
+			return m, nil
 		}
+		cmd := m.finishToolCalls(msg.calls, msg.results)
 		m.updateConversationList()
 		m.updateViewport()
+		return m, tea.Batch(cmd, m.spinner.Tick)
+
+	case msgEditorDone:
+		target := msg.target
+		m.editorTarget = editorTargetNone
+		if msg.err != nil {
+			m.err = fmt.Errorf("editor failed: %v", msg.err)
+			return m, nil
+		}
+		switch target {
+		case editorTargetTextarea:
+			m.textarea.SetValue(strings.TrimRight(msg.content, "\n"))
+		case editorTargetMessage:
+			m.applyEditedMessage(msg.content)
+		}
+		return m, nil
+
+	case msgTitleGenerated:
+		if conv := m.findConversation(msg.convID); conv != nil {
+			conv.Name = msg.title
+			if err := m.db.RenameConversation(msg.convID, msg.title); err != nil {
+				m.err = err
+			}
+			m.updateConversationList()
+		}
+		return m, nil
+
+	case spinner.TickMsg:
+		if m.streaming {
+			var spCmd tea.Cmd
+			m.spinner, spCmd = m.spinner.Update(msg)
+			m.elapsed = time.Since(m.startTime)
+			return m, spCmd
+		}
+		return m, nil
 	}
 
 	// Update child components
@@ -270,7 +663,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 	m.viewport, vpCmd = m.viewport.Update(msg)
 
-	return m, tea.Batch(tiCmd, vpCmd, clCmd)
+	var curCmd tea.Cmd
+	if m.streaming {
+		m.cursor, curCmd = m.cursor.Update(msg)
+	}
+
+	return m, tea.Batch(tiCmd, vpCmd, clCmd, curCmd)
 }
 
 func (m *Model) updateConversationList() {
@@ -279,7 +677,7 @@ func (m *Model) updateConversationList() {
 		items[i] = conv
 	}
 	m.convList.SetItems(items)
-	
+
 	// Select current conversation in list
 	for i, conv := range m.conversations {
 		if conv.ID == m.currentConvID {
@@ -290,8 +688,13 @@ func (m *Model) updateConversationList() {
 }
 
 func (m *Model) getCurrentConversation() *models.Conversation {
+	return m.findConversation(m.currentConvID)
+}
+
+// findConversation looks up a conversation by ID, or nil if none matches.
+func (m *Model) findConversation(id string) *models.Conversation {
 	for i := range m.conversations {
-		if m.conversations[i].ID == m.currentConvID {
+		if m.conversations[i].ID == id {
 			return &m.conversations[i]
 		}
 	}
@@ -300,7 +703,8 @@ func (m *Model) getCurrentConversation() *models.Conversation {
 
 func (m *Model) updateViewport() {
 	var content strings.Builder
-	
+	m.invalidateMessageCacheIfStale()
+
 	currentConv := m.getCurrentConversation()
 	if currentConv == nil || len(currentConv.Messages) == 0 {
 		content.WriteString("Welcome to the AI Chat Interface!\n")
@@ -308,31 +712,64 @@ func (m *Model) updateViewport() {
 		content.WriteString(HelpStyle.Render("Controls:\n"))
 		content.WriteString(HelpStyle.Render("• Tab - Switch between sidebar and chat\n"))
 		content.WriteString(HelpStyle.Render("• Ctrl+N - New conversation\n"))
+		content.WriteString(HelpStyle.Render("• In the sidebar: n - New, d - Delete, r - Rename, Shift+R - Regenerate title, x - Export\n"))
 		content.WriteString(HelpStyle.Render("• Enter - Send message / Select conversation\n"))
+		content.WriteString(HelpStyle.Render("• Ctrl+E - Edit last message (branches the conversation)\n"))
+		content.WriteString(HelpStyle.Render("• [ / ] - Cycle branches of the last message\n"))
+		content.WriteString(HelpStyle.Render("• Ctrl+X - Cancel a streaming reply\n"))
+		content.WriteString(HelpStyle.Render("• Ctrl+B - Pick a model for this conversation\n"))
+		content.WriteString(HelpStyle.Render("• Ctrl+A - Pick an agent for this conversation\n"))
+		content.WriteString(HelpStyle.Render("• Ctrl+T - Toggle tool call/result details\n"))
+		content.WriteString(HelpStyle.Render("• Ctrl+W - Toggle word-wrap\n"))
+		content.WriteString(HelpStyle.Render("• Ctrl+R - Toggle raw/rendered markdown\n"))
+		content.WriteString(HelpStyle.Render("• Ctrl+S - Edit this conversation's system prompt\n"))
+		content.WriteString(HelpStyle.Render("• Ctrl+P - Apply a prompt from the prompt library\n"))
+		content.WriteString(HelpStyle.Render("• Ctrl+K - Navigate messages with j/k; e - $EDITOR, r - retry, c - continue\n"))
+		content.WriteString(HelpStyle.Render("• / - Search across all conversations\n"))
 		content.WriteString(HelpStyle.Render("• Ctrl+C / Esc - Quit\n\n"))
 	} else {
-		for _, msg := range currentConv.Messages {
+		m.messageLineOffsets = make(map[int64]int)
+		for i, msg := range currentConv.Messages {
 			timeStr := msg.Time.Format("15:04:05")
-			
-			if msg.Role == "user" {
-				content.WriteString(MessageStyle.Render(
-					UserStyle.Render("You") + " " + 
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#666666")).Render("["+timeStr+"]") + "\n" +
-					msg.Content + "\n\n",
+			m.messageLineOffsets[msg.ID] = strings.Count(content.String(), "\n")
+
+			branchTag := ""
+			if i == len(currentConv.Messages)-1 && m.branchTotal > 1 {
+				branchTag = " " + HelpStyle.Render(fmt.Sprintf("[%d/%d]", m.branchPosition, m.branchTotal))
+			}
+
+			// Message-navigation mode (selectedMessage >= 0, see editor.go)
+			// highlights whichever message the j/k cursor is on.
+			style := MessageStyle
+			if i == m.selectedMessage {
+				style = SelectedMessageStyle
+			}
+
+			switch {
+			case msg.ToolCallID != "" && msg.Role == "assistant":
+				content.WriteString(style.Render(m.renderToolBlock("called "+msg.Name, msg.Content)))
+			case msg.Role == "tool":
+				content.WriteString(style.Render(m.renderToolBlock(msg.Name+" result", msg.Content)))
+			case msg.Role == "user":
+				content.WriteString(style.Render(
+					UserStyle.Render("You") + " " +
+						lipgloss.NewStyle().Foreground(lipgloss.Color("#666666")).Render("["+timeStr+"]") + branchTag + "\n" +
+						msg.Content + "\n\n",
 				))
-			} else {
-				content.WriteString(MessageStyle.Render(
+			default:
+				content.WriteString(style.Render(
 					AssistantStyle.Render("Assistant") + " " +
-					lipgloss.NewStyle().Foreground(lipgloss.Color("#666666")).Render("["+timeStr+"]") + "\n" +
-					msg.Content + "\n\n",
+						lipgloss.NewStyle().Foreground(lipgloss.Color("#666666")).Render("["+timeStr+"]") + branchTag + "\n" +
+						m.renderedMessageContent(msg) + "\n",
 				))
 			}
 		}
 	}
 
-	if m.loading {
+	if m.streaming {
 		content.WriteString(MessageStyle.Render(
-			LoadingStyle.Render("Assistant is typing...") + "\n",
+			AssistantStyle.Render("Assistant") + "\n" +
+				m.pendingContent + m.cursor.View(),
 		))
 	}
 
@@ -347,50 +784,47 @@ func (m *Model) updateViewport() {
 	m.viewport.GotoBottom()
 }
 
-func (m Model) sendMessage(content string) tea.Cmd {
-	return func() tea.Msg {
-		ctx := context.Background()
-		
-		messages := []openai.ChatCompletionMessage{
-			{
-				Role:    openai.ChatMessageRoleSystem,
-				Content: "You are a helpful AI assistant. Provide clear, concise, and helpful responses.",
-			},
-		}
-
-		// Get current conversation messages
-		currentConv := m.getCurrentConversation()
-		if currentConv != nil {
-			for _, msg := range currentConv.Messages {
-				var role string
-				if msg.Role == "user" {
-					role = openai.ChatMessageRoleUser
-				} else {
-					role = openai.ChatMessageRoleAssistant
-				}
-				messages = append(messages, openai.ChatCompletionMessage{
-					Role:    role,
-					Content: msg.Content,
-				})
-			}
-		}
-
-		resp, err := m.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-			Model:    openai.GPT3Dot5Turbo,
-			Messages: messages,
-			MaxTokens: 1000,
-		})
-
-		if err != nil {
-			return ResponseMsg{Err: err}
-		}
+// scrollViewportToMessage scrolls the viewport so id's rendered line is at
+// the top, using the offsets updateViewport recorded for the conversation
+// currently loaded into it. It's a no-op if id isn't in the active path.
+func (m *Model) scrollViewportToMessage(id int64) {
+	if line, ok := m.messageLineOffsets[id]; ok {
+		m.viewport.SetYOffset(line)
+	}
+}
 
-		if len(resp.Choices) == 0 {
-			return ResponseMsg{Err: fmt.Errorf("no response from API")}
-		}
+// renderToolBlock renders a tool call or tool result as a single collapsed
+// line by default (just label, dimmed), expanding to show body in full when
+// m.showToolResults is toggled on with Ctrl+T.
+func (m *Model) renderToolBlock(label, body string) string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("#666666"))
+	if !m.showToolResults {
+		return dim.Render("🔧 "+label) + "\n\n"
+	}
+	return dim.Render("🔧 "+label) + "\n" + body + "\n\n"
+}
 
-		return ResponseMsg{Content: resp.Choices[0].Message.Content}
+// renderStatusLine shows the live spinner, elapsed time, token count, and
+// tokens/second while a reply is streaming, plus the cancel hint. While the
+// stream is tearing down after a cancel it shows a "Cancelling…" line
+// instead. It renders to nothing once the stream is done so it doesn't eat a
+// line of the viewport.
+func (m Model) renderStatusLine() string {
+	if m.cancelling {
+		return HelpStyle.Render(fmt.Sprintf("%s Cancelling…\n", m.spinner.View()))
 	}
+	if !m.streaming {
+		return ""
+	}
+	elapsed := time.Since(m.startTime).Round(time.Second)
+	var rate float64
+	if m.elapsed > 0 {
+		rate = float64(m.tokenCount) / m.elapsed.Seconds()
+	}
+	return HelpStyle.Render(fmt.Sprintf(
+		"%s generating… %s • %d tokens • %.1f tok/s • Ctrl+X to cancel\n",
+		m.spinner.View(), elapsed, m.tokenCount, rate,
+	))
 }
 
 // View renders the UI
@@ -401,23 +835,59 @@ func (m Model) View() string {
 
 	// Create sidebar
 	sidebarContent := m.convList.View()
+	switch m.focus {
+	case FocusDeleteConfirm:
+		name := ""
+		if conv := m.findConversation(m.pendingDeleteID); conv != nil {
+			name = conv.Name
+		}
+		sidebarContent = fmt.Sprintf("Delete %q?\n\ny/n", name)
+	case FocusRename:
+		sidebarContent = "Rename conversation:\n\n" + m.renameInput.View()
+	case FocusExportPath:
+		sidebarContent = "Export to (writes .yaml and .md):\n\n" + m.exportPathInput.View()
+	}
 	var sidebar string
-	if m.focus == FocusSidebar {
-		sidebar = SidebarFocusedStyle.Width(m.sidebarWidth).Height(m.height-1).Render(sidebarContent)
+	if m.focus == FocusSidebar || m.focus == FocusDeleteConfirm || m.focus == FocusRename || m.focus == FocusExportPath {
+		sidebar = SidebarFocusedStyle.Width(m.sidebarWidth).Height(m.height - 1).Render(sidebarContent)
 	} else {
-		sidebar = SidebarStyle.Width(m.sidebarWidth).Height(m.height-1).Render(sidebarContent)
+		sidebar = SidebarStyle.Width(m.sidebarWidth).Height(m.height - 1).Render(sidebarContent)
 	}
 
 	// Create chat area
 	chatWidth := m.width - m.sidebarWidth - 2
 	chatHeader := TitleStyle.Width(chatWidth).Render("AI Chat Interface")
+	statusLine := m.renderStatusLine()
 	chatViewport := m.viewport.View()
 	chatInput := m.textarea.View()
-	
+	if m.focus == FocusModelPicker {
+		chatInput = m.modelList.View()
+	}
+	if m.focus == FocusAgentPicker {
+		chatInput = m.agentPickerList.View()
+	}
+	if m.focus == FocusSearch {
+		if m.searching {
+			chatInput = m.searchResultsList.View()
+		} else {
+			chatInput = m.searchInput.View()
+		}
+	}
+	if m.focus == FocusSystemPrompt {
+		chatViewport = m.systemPromptInput.View()
+		chatInput = HelpStyle.Render("Esc to save and return • Ctrl+P to save to the prompt library")
+	}
+	if m.focus == FocusPromptLibrary {
+		chatInput = m.promptLibraryList.View()
+	}
+	if m.focus == FocusPromptSaveName {
+		chatInput = "Save prompt as:\n\n" + m.promptSaveNameInput.View()
+	}
+
 	chatArea := ChatStyle.Width(chatWidth).Render(
-		fmt.Sprintf("%s\n%s\n%s", chatHeader, chatViewport, chatInput),
+		fmt.Sprintf("%s\n%s%s\n%s", chatHeader, statusLine, chatViewport, chatInput),
 	)
 
 	// Combine sidebar and chat area
 	return lipgloss.JoinHorizontal(lipgloss.Top, sidebar, chatArea)
-}
\ No newline at end of file
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"cog/internal/backends"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// modelItem adapts a backends.ModelInfo to list.Item, tagged with the
+// provider it came from so the picker can list models from every
+// configured backend while still knowing which one to switch the
+// conversation to on selection.
+type modelItem struct {
+	backends.ModelInfo
+	Backend string
+}
+
+func (i modelItem) FilterValue() string { return i.Name }
+func (i modelItem) Title() string       { return i.Name }
+func (i modelItem) Description() string { return i.Backend + " • " + i.ID }
+
+// openModelPicker lists the models of every registered backend, grouped by
+// provider, and switches focus to the picker. Each backend's ListModels call
+// happens inline on the UI goroutine; cog's model lists are small and
+// uncached, so this is a short, acceptable blip rather than something worth
+// streaming. A backend that errors (e.g. Ollama with no local server
+// running) is skipped rather than aborting the whole listing.
+func (m *Model) openModelPicker() {
+	var items []list.Item
+	for _, name := range m.registry.Names() {
+		backend, err := m.registry.Get(name)
+		if err != nil {
+			continue
+		}
+		models, err := backend.ListModels(context.Background())
+		if err != nil {
+			continue
+		}
+		for _, mi := range models {
+			items = append(items, modelItem{ModelInfo: mi, Backend: backend.Name()})
+		}
+	}
+
+	if len(items) == 0 {
+		m.err = fmt.Errorf("no models available from any configured backend")
+		return
+	}
+
+	m.modelList.SetItems(items)
+	m.modelList.Title = "Models"
+	m.focus = FocusModelPicker
+}
+
+// selectModel applies the model picker's current selection — both the
+// provider and the model — to the active conversation and persists it, so
+// different conversations can each use a different backend.
+func (m *Model) selectModel() {
+	selected, ok := m.modelList.SelectedItem().(modelItem)
+	if !ok {
+		return
+	}
+
+	for i := range m.conversations {
+		if m.conversations[i].ID != m.currentConvID {
+			continue
+		}
+		m.conversations[i].Backend = selected.Backend
+		m.conversations[i].Model = selected.ID
+		if err := m.db.SaveConversation(m.conversations[i]); err != nil {
+			m.err = fmt.Errorf("failed to save conversation: %v", err)
+		}
+		break
+	}
+}
+
+// updateModelPicker forwards msg to the model list while the picker has
+// focus, handling Enter (select) and Esc (cancel) itself.
+func (m Model) updateModelPicker(msg tea.Msg) (Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.Type {
+		case tea.KeyEsc:
+			m.focus = FocusChat
+			return m, nil
+		case tea.KeyEnter:
+			m.selectModel()
+			m.focus = FocusChat
+			m.updateViewport()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.modelList, cmd = m.modelList.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"cog/internal/storage"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// searchHitItem adapts a storage.SearchHit to list.Item so it can be
+// rendered by the same bubbles/list widget the sidebar and pickers use.
+type searchHitItem storage.SearchHit
+
+func (i searchHitItem) FilterValue() string { return i.Snippet }
+func (i searchHitItem) Title() string       { return highlightSnippet(i.Snippet) }
+func (i searchHitItem) Description() string { return i.ConversationID }
+
+var searchMarkStyle = lipgloss.NewStyle().Bold(true).Underline(true)
+
+// highlightSnippet turns a snippet()-produced "...<mark>term</mark>..." into
+// the same text with the marked portions bolded for the terminal.
+func highlightSnippet(snippet string) string {
+	parts := strings.Split(snippet, "<mark>")
+	out := parts[0]
+	for _, p := range parts[1:] {
+		end := strings.Index(p, "</mark>")
+		if end < 0 {
+			out += p
+			continue
+		}
+		out += searchMarkStyle.Render(p[:end]) + p[end+len("</mark>"):]
+	}
+	return out
+}
+
+// openSearch resets and focuses the search overlay.
+func (m *Model) openSearch() {
+	m.searchInput.SetValue("")
+	m.searchInput.Focus()
+	m.searching = false
+	m.searchResultsList.SetItems(nil)
+	m.focus = FocusSearch
+}
+
+// closeSearch leaves the search overlay and returns focus to the chat pane.
+func (m *Model) closeSearch() {
+	m.searchInput.Blur()
+	m.searching = false
+	m.focus = FocusChat
+	m.textarea.Focus()
+}
+
+// runSearch queries messages_fts for the overlay's current input and shows
+// the hits in searchResultsList, handing focus from the query box to the
+// results list.
+func (m *Model) runSearch() {
+	query := strings.TrimSpace(m.searchInput.Value())
+	if query == "" {
+		return
+	}
+
+	hits, err := m.db.SearchMessages(query)
+	if err != nil {
+		m.err = fmt.Errorf("search failed: %v", err)
+		return
+	}
+
+	items := make([]list.Item, len(hits))
+	for i, h := range hits {
+		items[i] = searchHitItem(h)
+	}
+	m.searchResultsList.SetItems(items)
+	m.searchResultsList.Title = fmt.Sprintf("Results for %q (%d)", query, len(hits))
+	m.searching = true
+}
+
+// jumpToSearchHit switches to the selected hit's conversation and scrolls the
+// viewport so the matching message is visible.
+func (m *Model) jumpToSearchHit() {
+	selected, ok := m.searchResultsList.SelectedItem().(searchHitItem)
+	if !ok {
+		return
+	}
+
+	m.currentConvID = selected.ConversationID
+	m.refreshBranchInfo()
+	m.updateConversationList()
+	m.updateViewport()
+	m.scrollViewportToMessage(selected.MessageID)
+}
+
+// updateSearch forwards msg to whichever widget currently has focus inside
+// the search overlay — the query box until a search has run, then the
+// results list — handling Enter and Esc itself.
+func (m Model) updateSearch(msg tea.Msg) (Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.Type {
+		case tea.KeyEsc:
+			m.closeSearch()
+			return m, nil
+		case tea.KeyEnter:
+			if m.searching {
+				m.jumpToSearchHit()
+				m.closeSearch()
+			} else {
+				m.runSearch()
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.searching {
+		m.searchResultsList, cmd = m.searchResultsList.Update(msg)
+	} else {
+		m.searchInput, cmd = m.searchInput.Update(msg)
+	}
+	return m, cmd
+}
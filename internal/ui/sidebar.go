@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"fmt"
+
+	"cog/internal/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// selectedSidebarConversation looks up the conversation currently highlighted
+// in the sidebar list, or nil if the list is empty.
+func (m *Model) selectedSidebarConversation() *models.Conversation {
+	item, ok := m.convList.SelectedItem().(models.Conversation)
+	if !ok {
+		return nil
+	}
+	return m.findConversation(item.ID)
+}
+
+// createNewConversation starts a fresh conversation pinned to the default
+// backend/model/agent, persists it, and switches focus to the chat pane —
+// shared by Ctrl+N and the sidebar's "n" binding.
+func (m *Model) createNewConversation() {
+	newConv := NewConversation("New Chat", m.registry.Default(), m.defaultModel, m.defaultAgent)
+	m.conversations = append(m.conversations, newConv)
+	m.currentConvID = newConv.ID
+
+	if err := m.db.SaveConversation(newConv); err != nil {
+		m.err = fmt.Errorf("failed to save conversation: %v", err)
+	}
+
+	m.updateConversationList()
+	m.updateViewport()
+	m.focus = FocusChat
+	m.textarea.Focus()
+}
+
+// beginDeleteConversation opens the delete confirmation overlay for the
+// sidebar's currently selected conversation.
+func (m *Model) beginDeleteConversation() {
+	conv := m.selectedSidebarConversation()
+	if conv == nil {
+		return
+	}
+	m.pendingDeleteID = conv.ID
+	m.focus = FocusDeleteConfirm
+}
+
+// confirmDelete removes m.pendingDeleteID from the database and the in-memory
+// list, then falls back to another conversation (creating one if none are
+// left) so the chat pane is never left pointing at a deleted conversation.
+func (m *Model) confirmDelete() {
+	if m.pendingDeleteID == "" {
+		return
+	}
+
+	if err := m.db.DeleteConversation(m.pendingDeleteID); err != nil {
+		m.err = fmt.Errorf("failed to delete conversation: %v", err)
+	}
+
+	for i := range m.conversations {
+		if m.conversations[i].ID == m.pendingDeleteID {
+			m.conversations = append(m.conversations[:i], m.conversations[i+1:]...)
+			break
+		}
+	}
+
+	if m.currentConvID == m.pendingDeleteID {
+		if len(m.conversations) > 0 {
+			m.currentConvID = m.conversations[0].ID
+		} else {
+			m.currentConvID = ""
+		}
+	}
+	m.pendingDeleteID = ""
+
+	if len(m.conversations) == 0 {
+		m.createNewConversation()
+	}
+
+	m.refreshBranchInfo()
+	m.updateConversationList()
+	m.updateViewport()
+}
+
+// updateDeleteConfirm handles the sidebar's delete confirmation overlay: "y"
+// deletes, anything else (notably "n" and Esc) cancels.
+func (m Model) updateDeleteConfirm(msg tea.Msg) (Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyRunes:
+		if len(keyMsg.Runes) == 1 && keyMsg.Runes[0] == 'y' {
+			m.confirmDelete()
+		}
+		m.focus = FocusSidebar
+		return m, nil
+	default:
+		m.pendingDeleteID = ""
+		m.focus = FocusSidebar
+		return m, nil
+	}
+}
+
+// beginRenameConversation opens the rename text input overlay, pre-filled
+// with the sidebar's currently selected conversation's name.
+func (m *Model) beginRenameConversation() {
+	conv := m.selectedSidebarConversation()
+	if conv == nil {
+		return
+	}
+	m.renamingConvID = conv.ID
+	m.renameInput.SetValue(conv.Name)
+	m.renameInput.CursorEnd()
+	m.renameInput.Focus()
+	m.focus = FocusRename
+}
+
+// applyRename saves the rename input's current value as m.renamingConvID's
+// name, unless it was left blank.
+func (m *Model) applyRename() {
+	name := m.renameInput.Value()
+	if name == "" || m.renamingConvID == "" {
+		return
+	}
+
+	if conv := m.findConversation(m.renamingConvID); conv != nil {
+		conv.Name = name
+	}
+	if err := m.db.RenameConversation(m.renamingConvID, name); err != nil {
+		m.err = fmt.Errorf("failed to rename conversation: %v", err)
+	}
+	m.updateConversationList()
+}
+
+// updateRenameInput forwards msg to the rename text input while the overlay
+// has focus, handling Enter (apply) and Esc (cancel) itself.
+func (m Model) updateRenameInput(msg tea.Msg) (Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.Type {
+		case tea.KeyEsc:
+			m.renamingConvID = ""
+			m.renameInput.Blur()
+			m.focus = FocusSidebar
+			return m, nil
+		case tea.KeyEnter:
+			m.applyRename()
+			m.renamingConvID = ""
+			m.renameInput.Blur()
+			m.focus = FocusSidebar
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.renameInput, cmd = m.renameInput.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,261 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"cog/internal/backends"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// msgResponseChunk carries one incremental delta from the stream.
+type msgResponseChunk struct {
+	content string
+}
+
+// msgResponseDone signals that the stream finished, either because the
+// model reached a natural end, the request errored, the user cancelled it
+// with stopSignal, or the model asked to call one or more tools.
+type msgResponseDone struct {
+	cancelled bool
+	err       error
+	toolCalls []backends.ToolCall
+}
+
+// beginStreamingReply resets the streaming bookkeeping fields and kicks off
+// a reply against the current conversation's already-built message history,
+// batching the stream's first command with the spinner and reply cursor.
+// Callers that add a new user message (KeyEnter) or rewrite the history
+// first (retrySelectedMessage, continueLastReply) all funnel through here.
+func (m *Model) beginStreamingReply() tea.Cmd {
+	m.refreshBranchInfo()
+	m.loading = true
+	m.streaming = true
+	m.cancelling = false
+	m.streamConvID = m.currentConvID
+	m.pendingContent = ""
+	m.tokenCount = 0
+	m.startTime = time.Now()
+	m.elapsed = 0
+	m.updateConversationList()
+	m.updateViewport()
+	cursorCmd := m.cursor.Focus()
+	return tea.Batch(m.startStream(), m.spinner.Tick, cursorCmd)
+}
+
+// startStream kicks off a streaming chat completion against the current
+// conversation's backend and returns the command that waits on the first
+// event from replyChan.
+func (m *Model) startStream() tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.streamCancel = cancel
+	m.stopSignal = make(chan struct{})
+	m.replyChan = make(chan tea.Msg)
+
+	conv := m.getCurrentConversation()
+	var convBackend, convModel string
+	if conv != nil {
+		convBackend, convModel = conv.Backend, conv.Model
+	}
+	if convModel == "" {
+		// An agent's Model, if set, overrides the backend's own default.
+		convModel = m.currentAgent().Model
+	}
+
+	backend, err := m.registry.Get(convBackend)
+	if err != nil {
+		replyChan := m.replyChan
+		go func() {
+			replyChan <- msgResponseDone{err: err}
+			close(replyChan)
+		}()
+		return waitForStreamEvent(m.replyChan)
+	}
+
+	req := backends.ChatRequest{
+		Model:    convModel,
+		Messages: m.buildChatMessages(),
+		Tools:    m.buildToolDefs(),
+	}
+
+	go m.streamResponse(ctx, backend, req, m.replyChan, m.stopSignal)
+
+	return waitForStreamEvent(m.replyChan)
+}
+
+// waitForStreamEvent reads a single event off replyChan and hands it back to
+// the Bubble Tea runtime as a tea.Msg.
+func waitForStreamEvent(replyChan chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-replyChan
+	}
+}
+
+// buildChatMessages assembles the active system prompt plus the current
+// conversation's history into the provider-agnostic message slice a Backend
+// expects. A conversation's own SystemPrompt, if set, overrides its agent's.
+func (m *Model) buildChatMessages() []backends.ChatMessage {
+	currentConv := m.getCurrentConversation()
+
+	systemPrompt := m.currentAgent().SystemPrompt
+	if currentConv != nil && currentConv.SystemPrompt != "" {
+		systemPrompt = currentConv.SystemPrompt
+	}
+
+	messages := []backends.ChatMessage{
+		{Role: "system", Content: systemPrompt},
+	}
+
+	if currentConv != nil {
+		for _, msg := range currentConv.Messages {
+			chatMsg := backends.ChatMessage{
+				Role:       msg.Role,
+				Content:    msg.Content,
+				ToolCallID: msg.ToolCallID,
+				Name:       msg.Name,
+			}
+			// A tool-calling assistant turn is persisted as Content holding
+			// the call's arguments; translate it back into the ToolCalls
+			// form a Backend expects instead of sending it as plain text.
+			if msg.Role == "assistant" && msg.ToolCallID != "" {
+				chatMsg.Content = ""
+				chatMsg.ToolCalls = []backends.ToolCall{{ID: msg.ToolCallID, Name: msg.Name, Arguments: msg.Content}}
+			}
+			messages = append(messages, chatMsg)
+		}
+	}
+
+	// continueLastReply leaves the history ending on the assistant's own
+	// message; nudge the model to extend it instead of treating it as
+	// something to reply to. This instruction is never persisted — it's
+	// rebuilt fresh on every request, same as the system prompt.
+	if m.continuation {
+		messages = append(messages, backends.ChatMessage{
+			Role:    "user",
+			Content: "Continue your previous reply exactly where it left off. Do not repeat or summarize what you already said.",
+		})
+	}
+
+	return messages
+}
+
+// buildToolDefs translates the current conversation's agent's tool
+// allowlist into the provider-agnostic definitions a Backend needs to offer
+// them to the model.
+func (m *Model) buildToolDefs() []backends.ToolDef {
+	if m.toolRegistry == nil {
+		return nil
+	}
+
+	allowed := m.toolRegistry.Allowed(m.currentAgent().Tools)
+	defs := make([]backends.ToolDef, 0, len(allowed))
+	for _, t := range allowed {
+		var schema struct {
+			Description string          `json:"description"`
+			Parameters  json.RawMessage `json:"parameters"`
+		}
+		if err := json.Unmarshal([]byte(t.Schema()), &schema); err != nil {
+			continue
+		}
+		defs = append(defs, backends.ToolDef{
+			Name:        t.Name(),
+			Description: schema.Description,
+			Parameters:  schema.Parameters,
+		})
+	}
+	return defs
+}
+
+// streamResponse runs in its own goroutine. It opens a streaming chat
+// completion against backend, forwards each delta as a msgResponseChunk on
+// replyChan, and finishes with a single msgResponseDone. It tears down as
+// soon as stopSignal is closed, regardless of where the stream is.
+func (m *Model) streamResponse(ctx context.Context, backend backends.Backend, req backends.ChatRequest, replyChan chan tea.Msg, stopSignal chan struct{}) {
+	chunks, err := backend.Chat(ctx, req)
+	if err != nil {
+		replyChan <- msgResponseDone{err: err}
+		close(replyChan)
+		return
+	}
+
+	for {
+		select {
+		case <-stopSignal:
+			cancel := m.streamCancel
+			if cancel != nil {
+				cancel()
+			}
+			replyChan <- msgResponseDone{cancelled: true}
+			close(replyChan)
+			return
+		case chunk, ok := <-chunks:
+			if !ok {
+				replyChan <- msgResponseDone{}
+				close(replyChan)
+				return
+			}
+			if chunk.Err != nil {
+				if errors.Is(chunk.Err, context.Canceled) {
+					replyChan <- msgResponseDone{cancelled: true}
+				} else {
+					replyChan <- msgResponseDone{err: chunk.Err}
+				}
+				close(replyChan)
+				return
+			}
+			if len(chunk.ToolCalls) > 0 {
+				replyChan <- msgResponseDone{toolCalls: chunk.ToolCalls}
+				close(replyChan)
+				return
+			}
+			if chunk.Content != "" {
+				replyChan <- msgResponseChunk{content: chunk.Content}
+			}
+		}
+	}
+}
+
+// finishStream assembles the accumulated streaming content (if any) into a
+// final assistant message, appends it to the current conversation, and
+// checkpoints the whole conversation once — streaming itself never writes to
+// the database, so a long reply costs one write instead of one per token.
+// While m.continuation is set (see continueLastReply), the content is
+// appended onto the existing last assistant message instead of starting a
+// new one, and SaveConversation's update path persists the extended text in
+// place rather than inserting a branch.
+func (m *Model) finishStream() {
+	if m.pendingContent != "" {
+		for i := range m.conversations {
+			if m.conversations[i].ID != m.streamConvID {
+				continue
+			}
+			msgs := m.conversations[i].Messages
+			if m.continuation && len(msgs) > 0 {
+				extended := &msgs[len(msgs)-1]
+				extended.Content += m.pendingContent
+				m.invalidateMessageCache(extended.ID)
+			} else {
+				m.conversations[i].Messages = append(msgs, m.newAssistantMessage(m.pendingContent))
+			}
+			if err := m.db.SaveConversation(m.conversations[i]); err != nil {
+				m.err = err
+			}
+			m.refreshBranchInfo()
+			break
+		}
+	}
+	m.continuation = false
+
+	m.streaming = false
+	m.cancelling = false
+	m.loading = false
+	m.pendingContent = ""
+	m.streamConvID = ""
+	m.streamCancel = nil
+	m.stopSignal = nil
+	m.replyChan = nil
+	m.cursor.Blur()
+}
@@ -21,6 +21,15 @@ var (
 			PaddingLeft(2).
 			MarginBottom(1)
 
+	// SelectedMessageStyle marks the message the j/k navigation cursor is on
+	// (see Model.selectedMessage) with a left border instead of MessageStyle's
+	// plain padding.
+	SelectedMessageStyle = lipgloss.NewStyle().
+				PaddingLeft(1).
+				MarginBottom(1).
+				Border(lipgloss.NormalBorder(), false, false, false, true).
+				BorderForeground(lipgloss.Color("#25A065"))
+
 	LoadingStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FFB347")).
 			Italic(true)
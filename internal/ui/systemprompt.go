@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"cog/internal/prompts"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// promptItem adapts a prompts.Prompt to list.Item so it can be rendered by
+// the same bubbles/list widget the sidebar and pickers use.
+type promptItem prompts.Prompt
+
+func (i promptItem) FilterValue() string { return i.Name }
+func (i promptItem) Title() string       { return i.Name }
+func (i promptItem) Description() string {
+	if len(i.Text) > 60 {
+		return i.Text[:57] + "..."
+	}
+	return i.Text
+}
+
+// openSystemPromptEditor loads the current conversation's SystemPrompt
+// override (falling back to its agent's, so editing starts from what's
+// actually in effect) into the full-screen textarea and switches focus to
+// it.
+func (m *Model) openSystemPromptEditor() {
+	conv := m.getCurrentConversation()
+	if conv == nil {
+		return
+	}
+
+	value := conv.SystemPrompt
+	if value == "" {
+		value = m.currentAgent().SystemPrompt
+	}
+	m.systemPromptInput.SetValue(value)
+	m.systemPromptInput.Focus()
+	m.textarea.Blur()
+	m.focus = FocusSystemPrompt
+}
+
+// saveSystemPrompt persists the editor's current text as the active
+// conversation's SystemPrompt override.
+func (m *Model) saveSystemPrompt() {
+	for i := range m.conversations {
+		if m.conversations[i].ID != m.currentConvID {
+			continue
+		}
+		m.conversations[i].SystemPrompt = m.systemPromptInput.Value()
+		if err := m.db.SaveConversation(m.conversations[i]); err != nil {
+			m.err = fmt.Errorf("failed to save conversation: %v", err)
+		}
+		break
+	}
+}
+
+// closeSystemPromptEditor saves the edited prompt and returns focus to chat.
+func (m *Model) closeSystemPromptEditor() {
+	m.saveSystemPrompt()
+	m.systemPromptInput.Blur()
+	m.focus = FocusChat
+	m.textarea.Focus()
+}
+
+// updateSystemPrompt forwards msg to the full-screen textarea, handling Esc
+// (save and return to chat) and Ctrl+P (save the current text into the
+// prompt library under a name) itself.
+func (m Model) updateSystemPrompt(msg tea.Msg) (Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.Type {
+		case tea.KeyEsc:
+			m.closeSystemPromptEditor()
+			return m, nil
+		case tea.KeyCtrlP:
+			m.promptSaveNameInput.SetValue("")
+			m.promptSaveNameInput.Focus()
+			m.focus = FocusPromptSaveName
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.systemPromptInput, cmd = m.systemPromptInput.Update(msg)
+	return m, cmd
+}
+
+// updatePromptSaveName forwards msg to the save-as name input, handling
+// Enter (save m.systemPromptInput's current text under that name) and Esc
+// (cancel back to the system prompt editor) itself.
+func (m Model) updatePromptSaveName(msg tea.Msg) (Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.Type {
+		case tea.KeyEsc:
+			m.promptSaveNameInput.Blur()
+			m.focus = FocusSystemPrompt
+			return m, nil
+		case tea.KeyEnter:
+			name := strings.TrimSpace(m.promptSaveNameInput.Value())
+			if name != "" {
+				if err := prompts.Save(name, m.systemPromptInput.Value()); err != nil {
+					m.err = fmt.Errorf("failed to save prompt: %v", err)
+				} else {
+					m.reloadPromptLibrary()
+				}
+			}
+			m.promptSaveNameInput.Blur()
+			m.focus = FocusSystemPrompt
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.promptSaveNameInput, cmd = m.promptSaveNameInput.Update(msg)
+	return m, cmd
+}
+
+// reloadPromptLibrary re-reads the prompt library from disk and refreshes
+// promptLibraryList, so a prompt saved this session shows up immediately.
+func (m *Model) reloadPromptLibrary() {
+	all, err := prompts.LoadAll()
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.promptLibrary = all
+	items := make([]list.Item, len(all))
+	for i, p := range all {
+		items[i] = promptItem(p)
+	}
+	m.promptLibraryList.SetItems(items)
+}
+
+// openPromptLibrary switches focus to the saved-prompts list.
+func (m *Model) openPromptLibrary() {
+	m.focus = FocusPromptLibrary
+}
+
+// applySelectedPrompt sets the current conversation's SystemPrompt override
+// to the prompt library's current selection and persists it.
+func (m *Model) applySelectedPrompt() {
+	selected, ok := m.promptLibraryList.SelectedItem().(promptItem)
+	if !ok {
+		return
+	}
+
+	for i := range m.conversations {
+		if m.conversations[i].ID != m.currentConvID {
+			continue
+		}
+		m.conversations[i].SystemPrompt = selected.Text
+		if err := m.db.SaveConversation(m.conversations[i]); err != nil {
+			m.err = fmt.Errorf("failed to save conversation: %v", err)
+		}
+		break
+	}
+}
+
+// updatePromptLibrary forwards msg to the prompt list while the library has
+// focus, handling Enter (apply the selection to the current conversation)
+// and Esc (cancel) itself.
+func (m Model) updatePromptLibrary(msg tea.Msg) (Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.Type {
+		case tea.KeyEsc:
+			m.focus = FocusChat
+			return m, nil
+		case tea.KeyEnter:
+			m.applySelectedPrompt()
+			m.focus = FocusChat
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.promptLibraryList, cmd = m.promptLibraryList.Update(msg)
+	return m, cmd
+}
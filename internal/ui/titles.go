@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"context"
+	"strings"
+
+	"cog/internal/backends"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// msgTitleGenerated carries a freshly generated conversation title back from
+// GenerateTitle's side-call to the LLM.
+type msgTitleGenerated struct {
+	convID string
+	title  string
+}
+
+// GenerateTitle asks convID's backend to summarize the conversation in a
+// handful of words, using only its user/assistant messages — tool calls and
+// the system prompt would just dilute a one-shot summary. The reply is
+// streamed like any other, but collected in full before it is returned: the
+// result is a handful of words, not worth showing incrementally.
+func (m *Model) GenerateTitle(convID string) tea.Cmd {
+	conv := m.findConversation(convID)
+	if conv == nil {
+		return nil
+	}
+
+	backend, err := m.registry.Get(conv.Backend)
+	if err != nil {
+		return nil
+	}
+
+	model := conv.Model
+	if model == "" {
+		model = m.currentAgent().Model
+	}
+
+	messages := []backends.ChatMessage{
+		{Role: "system", Content: "Summarize this conversation in 6 words or fewer. Reply with only the summary, no punctuation or quotes."},
+	}
+	for _, msg := range conv.Messages {
+		if msg.ToolCallID != "" || (msg.Role != "user" && msg.Role != "assistant") {
+			continue
+		}
+		messages = append(messages, backends.ChatMessage{Role: msg.Role, Content: msg.Content})
+	}
+
+	req := backends.ChatRequest{Model: model, Messages: messages}
+
+	return func() tea.Msg {
+		chunks, err := backend.Chat(context.Background(), req)
+		if err != nil {
+			return nil
+		}
+
+		var title strings.Builder
+		for chunk := range chunks {
+			if chunk.Err != nil {
+				return nil
+			}
+			title.WriteString(chunk.Content)
+		}
+
+		trimmed := strings.TrimSpace(title.String())
+		if trimmed == "" {
+			return nil
+		}
+		return msgTitleGenerated{convID: convID, title: trimmed}
+	}
+}
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"time"
+
+	"cog/internal/backends"
+	"cog/internal/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// msgToolCallsDone carries the result of runToolCallsAsync: either every
+// call's result, in order, or cancelled if stopSignal closed before they
+// all finished.
+type msgToolCallsDone struct {
+	calls     []backends.ToolCall
+	results   []string
+	cancelled bool
+}
+
+// startToolCalls kicks off calls in a goroutine against a fresh cancellable
+// context, the same shape startStream uses for the model request itself, so
+// a tool call (notably ExecTool, which can run an arbitrary shell command)
+// can't block the Bubble Tea event loop and can still be cancelled with
+// Ctrl+X while it's running.
+func (m *Model) startToolCalls(calls []backends.ToolCall) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.streamCancel = cancel
+	m.stopSignal = make(chan struct{})
+	m.replyChan = make(chan tea.Msg)
+
+	go m.runToolCallsAsync(ctx, calls, m.replyChan, m.stopSignal)
+
+	return waitForStreamEvent(m.replyChan)
+}
+
+// runToolCallsAsync runs in its own goroutine. It runs each call in turn,
+// but races it against stopSignal rather than just waiting on it between
+// calls: closing stopSignal cancels ctx immediately, so a call already in
+// flight (e.g. ExecTool's exec.CommandContext) is actually torn down
+// instead of being left to run to completion.
+func (m *Model) runToolCallsAsync(ctx context.Context, calls []backends.ToolCall, replyChan chan tea.Msg, stopSignal chan struct{}) {
+	results := make([]string, len(calls))
+	for i, call := range calls {
+		done := make(chan string, 1)
+		go func() { done <- m.runTool(ctx, call) }()
+
+		select {
+		case <-stopSignal:
+			if cancel := m.streamCancel; cancel != nil {
+				cancel()
+			}
+			replyChan <- msgToolCallsDone{cancelled: true}
+			close(replyChan)
+			return
+		case results[i] = <-done:
+		}
+	}
+	replyChan <- msgToolCallsDone{calls: calls, results: results}
+	close(replyChan)
+}
+
+// finishToolCalls appends an assistant "call" message and a "tool" result
+// message per call to the current conversation, persists it, and starts
+// another stream turn so the model can see the results and continue.
+// Persisting tool calls as plain messages (rather than a side channel) is
+// what lets branching, search, and export treat them like any other part of
+// the conversation.
+func (m *Model) finishToolCalls(calls []backends.ToolCall, results []string) tea.Cmd {
+	for i := range m.conversations {
+		if m.conversations[i].ID != m.streamConvID {
+			continue
+		}
+
+		for j, call := range calls {
+			m.conversations[i].Messages = append(m.conversations[i].Messages, models.Message{
+				Role:       "assistant",
+				Content:    call.Arguments,
+				Time:       time.Now(),
+				ToolCallID: call.ID,
+				Name:       call.Name,
+			})
+			m.conversations[i].Messages = append(m.conversations[i].Messages, models.Message{
+				Role:       "tool",
+				Content:    results[j],
+				Time:       time.Now(),
+				ToolCallID: call.ID,
+				Name:       call.Name,
+			})
+		}
+
+		if err := m.db.SaveConversation(m.conversations[i]); err != nil {
+			m.err = err
+		}
+		m.refreshBranchInfo()
+		break
+	}
+
+	m.pendingContent = ""
+	return m.startStream()
+}
+
+// runTool looks up and runs a single tool call against the current agent's
+// allowlist, returning its result or an error description as the "tool"
+// message's content either way — a denied or failing call is something the
+// model should see and can react to, not something cog should crash on. ctx
+// comes from runToolCallsAsync, so cancelling the call (Ctrl+X) actually
+// reaches a tool like ExecTool that honors it.
+func (m *Model) runTool(ctx context.Context, call backends.ToolCall) string {
+	if m.toolRegistry == nil {
+		return "error: no tools are available"
+	}
+
+	agent := m.currentAgent()
+	if !agent.HasTool(call.Name) {
+		return fmt.Sprintf("error: agent %q is not allowed to use tool %q", agent.Name, call.Name)
+	}
+
+	tool, ok := m.toolRegistry.Get(call.Name)
+	if !ok {
+		return fmt.Sprintf("error: unknown tool %q", call.Name)
+	}
+
+	result, err := tool.Run(ctx, call.Arguments)
+	if err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	return result
+}